@@ -0,0 +1,35 @@
+// Package audit records an immutable trail of feature-flag mutations, and
+// lets callers query that trail back out per flag key.
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Record is one mutation: who changed what, from which value to which, and
+// when, tagged with the request ID so it can be correlated with logs/traces.
+type Record struct {
+	RequestID string      `json:"request_id"`
+	Actor     string      `json:"actor"`
+	Key       string      `json:"key"`
+	OldValue  interface{} `json:"old_value"`
+	NewValue  interface{} `json:"new_value"`
+	TS        time.Time   `json:"ts"`
+}
+
+// Sink persists audit Records and serves them back for /audit?key=...
+// Implementations backed by a pure log (e.g. Kafka) may not support Query;
+// see ErrQueryNotSupported.
+type Sink interface {
+	Write(ctx context.Context, r Record) error
+	Query(ctx context.Context, key string) ([]Record, error)
+}
+
+// ErrUnknownBackend is returned when a caller selects an audit sink name
+// that does not match a known implementation.
+type ErrUnknownBackend string
+
+func (e ErrUnknownBackend) Error() string {
+	return "audit: unknown sink " + string(e)
+}
@@ -0,0 +1,74 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileSink appends every Record as a JSON line to a file, fsyncing after
+// each write, and serves Query by scanning the file — the same append-log
+// shape as wal.WAL, reused here because audit records have the same
+// durability and immutability requirements.
+type FileSink struct {
+	mu   sync.Mutex
+	f    *os.File
+	path string
+}
+
+// NewFileSink opens (creating if necessary) the audit log at path.
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{f: f, path: path}, nil
+}
+
+func (s *FileSink) Write(ctx context.Context, r Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := s.f.Write(line); err != nil {
+		return err
+	}
+	return s.f.Sync()
+}
+
+func (s *FileSink) Query(ctx context.Context, key string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, err
+		}
+		if r.Key == key {
+			out = append(out, r)
+		}
+	}
+	return out, scanner.Err()
+}
+
+// Close releases the underlying file handle.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.f.Close()
+}
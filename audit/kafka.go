@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// ErrQueryNotSupported is returned by KafkaSink.Query: Kafka is an
+// append-only log with no read-path for "every record matching this key",
+// so /audit?key=... isn't servable from the sink itself. Deployments that
+// need it should have a downstream consumer materialize records into a
+// queryable store and point /audit at that instead.
+var ErrQueryNotSupported = errors.New("audit: KafkaSink does not support Query; consume the topic downstream instead")
+
+// KafkaSink publishes every Record to a Kafka topic, keyed by flag key so a
+// downstream consumer can maintain a compacted, per-key view.
+type KafkaSink struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaSink builds a Sink that publishes to topic on the given brokers.
+func NewKafkaSink(brokers []string, topic string) *KafkaSink {
+	return &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(brokers...),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+		},
+	}
+}
+
+func (s *KafkaSink) Write(ctx context.Context, r Record) error {
+	value, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.writer.WriteMessages(ctx, kafka.Message{
+		Key:   []byte(r.Key),
+		Value: value,
+	})
+}
+
+func (s *KafkaSink) Query(ctx context.Context, key string) ([]Record, error) {
+	return nil, ErrQueryNotSupported
+}
+
+// Close flushes and closes the underlying Kafka writer.
+func (s *KafkaSink) Close() error {
+	return s.writer.Close()
+}
@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// StdoutSink writes every Record as a JSON line to an io.Writer (typically
+// os.Stdout) and keeps an in-memory copy so Query has something to serve,
+// since a log stream isn't itself queryable.
+type StdoutSink struct {
+	w io.Writer
+
+	mu      sync.Mutex
+	records []Record
+}
+
+// NewStdoutSink builds a Sink that writes to w.
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	return &StdoutSink{w: w}
+}
+
+func (s *StdoutSink) Write(ctx context.Context, r Record) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	s.records = append(s.records, r)
+	return nil
+}
+
+func (s *StdoutSink) Query(ctx context.Context, key string) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Record
+	for _, r := range s.records {
+		if r.Key == key {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,65 @@
+// Package auth validates bearer JWTs against a JWKS endpoint and enforces
+// role-based access control over the feature-flag endpoints: reader can
+// call /get, writer can call /set on flags in a namespace they own, admin
+// can do anything.
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// Claims is the subset of a validated JWT's claims the service acts on.
+type Claims struct {
+	Subject    string   `json:"sub"`
+	Roles      []string `json:"roles"`
+	Namespaces []string `json:"namespaces"`
+}
+
+// HasRole reports whether c was issued role.
+func (c Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanRead reports whether c may call /get or /evaluate: any authenticated
+// reader, writer, or admin.
+func (c Claims) CanRead() bool {
+	return c.HasRole("reader") || c.HasRole("writer") || c.HasRole("admin")
+}
+
+// CanWrite reports whether c may set key: an admin may set anything, a
+// writer only a key within one of their Namespaces.
+func (c Claims) CanWrite(key string) bool {
+	if c.HasRole("admin") {
+		return true
+	}
+	if !c.HasRole("writer") {
+		return false
+	}
+	for _, ns := range c.Namespaces {
+		if strings.HasPrefix(key, ns) {
+			return true
+		}
+	}
+	return false
+}
+
+type contextKey struct{}
+
+// WithClaims returns a context carrying claims, as set by the transport's
+// authentication RequestFunc before an endpoint runs.
+func WithClaims(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, contextKey{}, claims)
+}
+
+// FromContext returns the Claims carried by ctx, if the caller authenticated
+// successfully.
+func FromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(contextKey{}).(Claims)
+	return claims, ok
+}
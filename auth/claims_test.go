@@ -0,0 +1,50 @@
+package auth
+
+import "testing"
+
+func TestCanRead(t *testing.T) {
+	cases := []struct {
+		name  string
+		roles []string
+		want  bool
+	}{
+		{"reader", []string{"reader"}, true},
+		{"writer", []string{"writer"}, true},
+		{"admin", []string{"admin"}, true},
+		{"no roles", nil, false},
+		{"unrelated role", []string{"billing"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Claims{Roles: tc.roles}
+			if got := c.CanRead(); got != tc.want {
+				t.Fatalf("CanRead() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCanWrite(t *testing.T) {
+	cases := []struct {
+		name       string
+		roles      []string
+		namespaces []string
+		key        string
+		want       bool
+	}{
+		{"admin writes anything", []string{"admin"}, nil, "anything", true},
+		{"writer within namespace", []string{"writer"}, []string{"team-a."}, "team-a.flag1", true},
+		{"writer outside every namespace", []string{"writer"}, []string{"team-a."}, "team-b.flag1", false},
+		{"writer with no namespaces", []string{"writer"}, nil, "team-a.flag1", false},
+		{"reader cannot write", []string{"reader"}, []string{"team-a."}, "team-a.flag1", false},
+		{"no roles cannot write", nil, []string{"team-a."}, "team-a.flag1", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := Claims{Roles: tc.roles, Namespaces: tc.namespaces}
+			if got := c.CanWrite(tc.key); got != tc.want {
+				t.Fatalf("CanWrite(%q) = %v, want %v", tc.key, got, tc.want)
+			}
+		})
+	}
+}
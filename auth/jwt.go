@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/MicahParks/keyfunc/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// ErrInvalidToken is returned by Authenticate when the bearer token fails
+// signature verification or cannot be parsed.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// Authenticator validates bearer JWTs against a JWKS endpoint fetched once
+// at startup; the fetched key set is not refreshed afterwards.
+type Authenticator struct {
+	jwks *keyfunc.JWKS
+}
+
+// NewAuthenticator fetches the JWKS at jwksURL and builds an Authenticator
+// that validates tokens against it.
+func NewAuthenticator(jwksURL string) (*Authenticator, error) {
+	jwks, err := keyfunc.Get(jwksURL, keyfunc.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &Authenticator{jwks: jwks}, nil
+}
+
+// Authenticate validates tokenString and extracts its Claims.
+func (a *Authenticator) Authenticate(tokenString string) (Claims, error) {
+	token, err := jwt.Parse(tokenString, a.jwks.Keyfunc)
+	if err != nil || !token.Valid {
+		return Claims{}, ErrInvalidToken
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+
+	claims := Claims{
+		Subject:    stringClaim(mapClaims, "sub"),
+		Roles:      stringSliceClaim(mapClaims, "roles"),
+		Namespaces: stringSliceClaim(mapClaims, "namespaces"),
+	}
+	return claims, nil
+}
+
+// HTTPToContext is a go-kit transport/http.RequestFunc that validates the
+// request's bearer token, if any, and attaches its Claims to the context.
+// A missing or invalid token is not rejected here — it simply leaves the
+// context without Claims, so that RequireRead/RequireWrite can return
+// a uniform, endpoint-layer ErrUnauthenticated.
+func (a *Authenticator) HTTPToContext() func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		token := bearerToken(r.Header.Get("Authorization"))
+		if token == "" {
+			return ctx
+		}
+		claims, err := a.Authenticate(token)
+		if err != nil {
+			return ctx
+		}
+		return WithClaims(ctx, claims)
+	}
+}
+
+// UnaryServerInterceptor resolves the "authorization" metadata value on
+// unary RPCs into Claims, mirroring HTTPToContext for the gRPC transport.
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(a.contextFromMetadata(ctx), req)
+	}
+}
+
+// StreamServerInterceptor is the streaming-RPC equivalent of
+// UnaryServerInterceptor, used for WatchFlags.
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		wrapped := &authenticatedServerStream{
+			ServerStream: ss,
+			ctx:          a.contextFromMetadata(ss.Context()),
+		}
+		return handler(srv, wrapped)
+	}
+}
+
+func (a *Authenticator) contextFromMetadata(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx
+	}
+	token := bearerToken(values[0])
+	if token == "" {
+		return ctx
+	}
+	claims, err := a.Authenticate(token)
+	if err != nil {
+		return ctx
+	}
+	return WithClaims(ctx, claims)
+}
+
+// authenticatedServerStream overrides Context so downstream handlers see the
+// Claims-bearing context built by contextFromMetadata.
+type authenticatedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+func stringClaim(claims jwt.MapClaims, key string) string {
+	v, _ := claims[key].(string)
+	return v
+}
+
+func stringSliceClaim(claims jwt.MapClaims, key string) []string {
+	raw, ok := claims[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(raw))
+	for _, v := range raw {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
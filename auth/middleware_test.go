@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+)
+
+func okEndpoint(ctx context.Context, request interface{}) (interface{}, error) {
+	return "ok", nil
+}
+
+func TestRequireReadRejectsUnauthenticated(t *testing.T) {
+	_, err := RequireRead()(okEndpoint)(context.Background(), nil)
+	if err != ErrUnauthenticated {
+		t.Fatalf("err = %v, want %v", err, ErrUnauthenticated)
+	}
+}
+
+func TestRequireReadRejectsInsufficientRole(t *testing.T) {
+	ctx := WithClaims(context.Background(), Claims{Roles: []string{"billing"}})
+	_, err := RequireRead()(okEndpoint)(ctx, nil)
+	if err != ErrForbidden {
+		t.Fatalf("err = %v, want %v", err, ErrForbidden)
+	}
+}
+
+func TestRequireReadAllowsReader(t *testing.T) {
+	ctx := WithClaims(context.Background(), Claims{Roles: []string{"reader"}})
+	resp, err := RequireRead()(okEndpoint)(ctx, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp != "ok" {
+		t.Fatalf("resp = %v, want %q", resp, "ok")
+	}
+}
+
+func keyOf(request interface{}) string {
+	return request.(string)
+}
+
+func TestRequireWriteEnforcesNamespaceOwnership(t *testing.T) {
+	mw := RequireWrite(keyOf)
+
+	var next kitendpoint.Endpoint = okEndpoint
+
+	if _, err := mw(next)(context.Background(), "team-a.flag1"); err != ErrUnauthenticated {
+		t.Fatalf("no claims: err = %v, want %v", err, ErrUnauthenticated)
+	}
+
+	ctx := WithClaims(context.Background(), Claims{Roles: []string{"writer"}, Namespaces: []string{"team-a."}})
+	if _, err := mw(next)(ctx, "team-b.flag1"); err != ErrForbidden {
+		t.Fatalf("wrong namespace: err = %v, want %v", err, ErrForbidden)
+	}
+	if _, err := mw(next)(ctx, "team-a.flag1"); err != nil {
+		t.Fatalf("own namespace: unexpected error: %v", err)
+	}
+}
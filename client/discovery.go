@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+	"github.com/go-kit/kit/sd"
+	kitconsul "github.com/go-kit/kit/sd/consul"
+	"github.com/go-kit/kit/sd/lb"
+	"github.com/go-kit/log"
+	consulapi "github.com/hashicorp/consul/api"
+	"google.golang.org/grpc"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/endpoint"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+)
+
+var errSubscribeNotSupported = errors.New("client: Subscribe is not supported over the discovered, load-balanced client")
+
+// DiscoveryOptions configures a load-balanced, retrying client built over
+// Consul service discovery.
+type DiscoveryOptions struct {
+	ConsulAddr  string
+	ServiceName string
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+// discoveredClient is a service.Service whose methods each round-robin
+// across healthy instances of a discovered service, retrying a failed call
+// against a different instance.
+type discoveredClient struct {
+	get  kitendpoint.Endpoint
+	set  kitendpoint.Endpoint
+	list kitendpoint.Endpoint
+}
+
+// NewDiscovered builds a service.Service that discovers healthy instances of
+// opts.ServiceName via the Consul agent at opts.ConsulAddr, round-robins
+// calls across them, and retries a failed call against a different instance
+// up to opts.MaxAttempts times or until opts.Timeout elapses.
+func NewDiscovered(opts DiscoveryOptions, logger log.Logger) (service.Service, error) {
+	consulClient, err := consulapi.NewClient(&consulapi.Config{Address: opts.ConsulAddr})
+	if err != nil {
+		return nil, err
+	}
+	sdClient := kitconsul.NewClient(consulClient)
+	instancer := kitconsul.NewInstancer(sdClient, logger, opts.ServiceName, nil, true)
+
+	return &discoveredClient{
+		get:  retryingEndpoint(instancer, logger, opts, getFactory),
+		set:  retryingEndpoint(instancer, logger, opts, setFactory),
+		list: retryingEndpoint(instancer, logger, opts, listFactory),
+	}, nil
+}
+
+func retryingEndpoint(instancer sd.Instancer, logger log.Logger, opts DiscoveryOptions, factory sd.Factory) kitendpoint.Endpoint {
+	endpointer := sd.NewEndpointer(instancer, factory, logger)
+	balancer := lb.NewRoundRobin(endpointer)
+	return lb.Retry(opts.MaxAttempts, opts.Timeout, balancer)
+}
+
+func (c *discoveredClient) GetFeatureFlag(ctx context.Context, key string, evalContext flags.Context) (interface{}, error) {
+	resp, err := c.get(ctx, endpoint.GetFeatureFlagRequest{Key: key, Context: evalContext})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(endpoint.GetFeatureFlagResponse).Value, nil
+}
+
+func (c *discoveredClient) SetFeatureFlag(ctx context.Context, key string, flag flags.Flag) error {
+	_, err := c.set(ctx, endpoint.SetFeatureFlagRequest{Key: key, Flag: flag})
+	return err
+}
+
+func (c *discoveredClient) ListFlags(ctx context.Context) (map[string]flags.Flag, error) {
+	resp, err := c.list(ctx, endpoint.ListFlagsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(endpoint.ListFlagsResponse).Flags, nil
+}
+
+// Subscribe is not supported over the load-balanced client: a long-lived
+// stream can't be round-robined or retried against a different instance
+// mid-flight the way a unary call can. Callers that need to watch for
+// mutations should dial an instance directly via New and call Subscribe on
+// that client instead.
+func (c *discoveredClient) Subscribe(ctx context.Context, sinceVersion int64) (<-chan service.Event, error) {
+	return nil, errSubscribeNotSupported
+}
+
+func (c *discoveredClient) QueryAudit(ctx context.Context, key string) ([]audit.Record, error) {
+	return nil, errAuditNotSupported
+}
+
+// dialInstance builds a service.Service backed by a gRPC connection to
+// instance ("host:port" as reported by the discovery backend).
+func dialInstance(instance string) (service.Service, io.Closer, error) {
+	conn, err := grpc.Dial(instance, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, err
+	}
+	return New(conn), conn, nil
+}
+
+func getFactory(instance string) (kitendpoint.Endpoint, io.Closer, error) {
+	svc, closer, err := dialInstance(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(endpoint.GetFeatureFlagRequest)
+		val, err := svc.GetFeatureFlag(ctx, req.Key, req.Context)
+		if err != nil {
+			return nil, err
+		}
+		return endpoint.GetFeatureFlagResponse{Value: val}, nil
+	}, closer, nil
+}
+
+func setFactory(instance string) (kitendpoint.Endpoint, io.Closer, error) {
+	svc, closer, err := dialInstance(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(endpoint.SetFeatureFlagRequest)
+		if err := svc.SetFeatureFlag(ctx, req.Key, req.Flag); err != nil {
+			return nil, err
+		}
+		return endpoint.SetFeatureFlagResponse{Success: true}, nil
+	}, closer, nil
+}
+
+func listFactory(instance string) (kitendpoint.Endpoint, io.Closer, error) {
+	svc, closer, err := dialInstance(instance)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		all, err := svc.ListFlags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return endpoint.ListFlagsResponse{Flags: all}, nil
+	}, closer, nil
+}
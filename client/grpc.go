@@ -0,0 +1,171 @@
+// Package client provides a gRPC client for the feature-flag service,
+// implementing service.Service so that callers can use a remote instance as
+// a local value without depending on go-kit/grpc or pb directly.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/endpoint"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/pb"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+)
+
+// errAuditNotSupported is returned by both grpcClient and discoveredClient:
+// the gRPC transport (pb.FeatureFlagServiceClient) has no Audit RPC;
+// /audit is served only by the HTTP transport. Callers that need audit
+// history should query the HTTP transport directly.
+var errAuditNotSupported = errors.New("client: QueryAudit is not supported over the gRPC transport")
+
+type grpcClient struct {
+	get  kitendpoint.Endpoint
+	set  kitendpoint.Endpoint
+	list kitendpoint.Endpoint
+	raw  pb.FeatureFlagServiceClient
+}
+
+// New builds a service.Service that calls a feature-flag instance over conn.
+func New(conn *grpc.ClientConn) service.Service {
+	return &grpcClient{
+		get: kitgrpc.NewClient(
+			conn, "pb.FeatureFlagService", "GetFeatureFlag",
+			encodeGetFeatureFlagRequest, decodeGetFeatureFlagResponse, pb.GetFeatureFlagResponse{},
+		).Endpoint(),
+		set: kitgrpc.NewClient(
+			conn, "pb.FeatureFlagService", "SetFeatureFlag",
+			encodeSetFeatureFlagRequest, decodeSetFeatureFlagResponse, pb.SetFeatureFlagResponse{},
+		).Endpoint(),
+		list: kitgrpc.NewClient(
+			conn, "pb.FeatureFlagService", "ListFlags",
+			encodeListFlagsRequest, decodeListFlagsResponse, pb.ListFlagsResponse{},
+		).Endpoint(),
+		raw: pb.NewFeatureFlagServiceClient(conn),
+	}
+}
+
+func (c *grpcClient) GetFeatureFlag(ctx context.Context, key string, evalContext flags.Context) (interface{}, error) {
+	resp, err := c.get(ctx, endpoint.GetFeatureFlagRequest{Key: key, Context: evalContext})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(endpoint.GetFeatureFlagResponse).Value, nil
+}
+
+func (c *grpcClient) SetFeatureFlag(ctx context.Context, key string, flag flags.Flag) error {
+	_, err := c.set(ctx, endpoint.SetFeatureFlagRequest{Key: key, Flag: flag})
+	return err
+}
+
+func (c *grpcClient) ListFlags(ctx context.Context) (map[string]flags.Flag, error) {
+	resp, err := c.list(ctx, endpoint.ListFlagsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(endpoint.ListFlagsResponse).Flags, nil
+}
+
+func (c *grpcClient) QueryAudit(ctx context.Context, key string) ([]audit.Record, error) {
+	return nil, errAuditNotSupported
+}
+
+// Subscribe streams mutations via the WatchFlags RPC. Unlike the HTTP
+// transport's /stream, the gRPC watch has no resume-from-version cursor, so
+// sinceVersion is ignored; callers that need resume should use /stream.
+func (c *grpcClient) Subscribe(ctx context.Context, sinceVersion int64) (<-chan service.Event, error) {
+	stream, err := c.raw.WatchFlags(ctx, &pb.WatchFlagsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan service.Event, 16)
+	go func() {
+		defer close(ch)
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			var value interface{}
+			if ev.ValueJson != "" {
+				if err := json.Unmarshal([]byte(ev.ValueJson), &value); err != nil {
+					continue
+				}
+			}
+			select {
+			case ch <- service.Event{
+				Key:     ev.Key,
+				Value:   value,
+				Version: ev.Version,
+				TS:      time.Unix(ev.UnixTs, 0).UTC(),
+			}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+func encodeGetFeatureFlagRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoint.GetFeatureFlagRequest)
+	var contextJSON string
+	if req.Context != nil {
+		b, err := json.Marshal(req.Context)
+		if err != nil {
+			return nil, err
+		}
+		contextJSON = string(b)
+	}
+	return &pb.GetFeatureFlagRequest{Key: req.Key, ContextJson: contextJSON}, nil
+}
+
+func decodeGetFeatureFlagResponse(_ context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.GetFeatureFlagResponse)
+	var value interface{}
+	if resp.ValueJson != "" {
+		if err := json.Unmarshal([]byte(resp.ValueJson), &value); err != nil {
+			return nil, err
+		}
+	}
+	return endpoint.GetFeatureFlagResponse{Value: value}, nil
+}
+
+func encodeSetFeatureFlagRequest(_ context.Context, request interface{}) (interface{}, error) {
+	req := request.(endpoint.SetFeatureFlagRequest)
+	b, err := json.Marshal(req.Flag)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.SetFeatureFlagRequest{Key: req.Key, FlagJson: string(b)}, nil
+}
+
+func decodeSetFeatureFlagResponse(_ context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.SetFeatureFlagResponse)
+	return endpoint.SetFeatureFlagResponse{Success: resp.Success}, nil
+}
+
+func encodeListFlagsRequest(_ context.Context, _ interface{}) (interface{}, error) {
+	return &pb.ListFlagsRequest{}, nil
+}
+
+func decodeListFlagsResponse(_ context.Context, grpcResp interface{}) (interface{}, error) {
+	resp := grpcResp.(*pb.ListFlagsResponse)
+	out := make(map[string]flags.Flag, len(resp.FlagsJson))
+	for key, v := range resp.FlagsJson {
+		var f flags.Flag
+		if err := json.Unmarshal([]byte(v), &f); err != nil {
+			return nil, err
+		}
+		out[key] = f
+	}
+	return endpoint.ListFlagsResponse{Flags: out}, nil
+}
@@ -0,0 +1,192 @@
+// Package endpoint adapts service.Service into go-kit endpoint.Endpoints, so
+// that every transport (HTTP, gRPC, ...) wraps the same request handling and
+// middleware chain instead of re-implementing it.
+package endpoint
+
+import (
+	"context"
+	"fmt"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/auth"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/metrics"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+	"github.com/build-a-thing-and-show/feature-flag-service/tracing"
+)
+
+// Endpoints collects every endpoint exposed by the feature-flag service. A
+// transport wraps each of these in its own request/response codec.
+type Endpoints struct {
+	GetFeatureFlagEndpoint kitendpoint.Endpoint
+	SetFeatureFlagEndpoint kitendpoint.Endpoint
+	EvaluateEndpoint       kitendpoint.Endpoint
+	EvaluateAllEndpoint    kitendpoint.Endpoint
+	ListFlagsEndpoint      kitendpoint.Endpoint
+	AuditEndpoint          kitendpoint.Endpoint
+}
+
+// MakeServerEndpoints builds the Endpoints for svc.
+//
+// If authenticator is non-nil, reads require an authenticated
+// reader/writer/admin and writes are restricted by RBAC namespace ownership;
+// if nil, auth is disabled (the default, matching --jwks-url being unset)
+// and every endpoint is open, as it was before auth existed.
+//
+// If m is non-nil, every endpoint's latency is recorded to
+// m.RequestLatency and GetFeatureFlag/Evaluate/EvaluateAll additionally
+// record resolved variations to m.EvaluationsTotal.
+func MakeServerEndpoints(svc service.Service, authenticator *auth.Authenticator, m *metrics.Metrics) Endpoints {
+	get := makeGetFeatureFlagEndpoint(svc, m)
+	set := makeSetFeatureFlagEndpoint(svc)
+	evaluate := makeEvaluateEndpoint(svc, m)
+	evaluateAll := makeEvaluateAllEndpoint(svc, m)
+	listFlags := makeListFlagsEndpoint(svc)
+	auditEndpoint := makeAuditEndpoint(svc)
+
+	if authenticator != nil {
+		get = auth.RequireRead()(get)
+		evaluate = auth.RequireRead()(evaluate)
+		evaluateAll = auth.RequireRead()(evaluateAll)
+		listFlags = auth.RequireRead()(listFlags)
+		auditEndpoint = auth.RequireRead()(auditEndpoint)
+		set = auth.RequireWrite(func(r interface{}) string {
+			return r.(SetFeatureFlagRequest).Key
+		})(set)
+	}
+
+	// OpenTelemetry spans wrap Get/SetFeatureFlag only; the other endpoints
+	// only get latency metrics below.
+	get = tracing.Middleware("GetFeatureFlag")(get)
+	set = tracing.Middleware("SetFeatureFlag")(set)
+
+	if m != nil {
+		get = metrics.InstrumentingMiddleware("GetFeatureFlag", m)(get)
+		set = metrics.InstrumentingMiddleware("SetFeatureFlag", m)(set)
+		evaluate = metrics.InstrumentingMiddleware("Evaluate", m)(evaluate)
+		evaluateAll = metrics.InstrumentingMiddleware("EvaluateAll", m)(evaluateAll)
+		listFlags = metrics.InstrumentingMiddleware("ListFlags", m)(listFlags)
+		auditEndpoint = metrics.InstrumentingMiddleware("Audit", m)(auditEndpoint)
+	}
+
+	return Endpoints{
+		GetFeatureFlagEndpoint: get,
+		SetFeatureFlagEndpoint: set,
+		EvaluateEndpoint:       evaluate,
+		EvaluateAllEndpoint:    evaluateAll,
+		ListFlagsEndpoint:      listFlags,
+		AuditEndpoint:          auditEndpoint,
+	}
+}
+
+// request and response types, shared by every transport.
+type GetFeatureFlagRequest struct {
+	Key     string        `json:"key"`
+	Context flags.Context `json:"context,omitempty"`
+}
+
+type GetFeatureFlagResponse struct {
+	Value interface{} `json:"value"`
+}
+
+type SetFeatureFlagRequest struct {
+	Key  string     `json:"key"`
+	Flag flags.Flag `json:"flag"`
+}
+
+type SetFeatureFlagResponse struct {
+	Success bool `json:"success"`
+}
+
+type EvaluateAllRequest struct {
+	Context flags.Context `json:"context,omitempty"`
+}
+
+type EvaluateAllResponse struct {
+	Values map[string]interface{} `json:"values"`
+}
+
+type ListFlagsRequest struct{}
+
+type ListFlagsResponse struct {
+	Flags map[string]flags.Flag `json:"flags"`
+}
+
+type AuditRequest struct {
+	Key string `json:"key"`
+}
+
+type AuditResponse struct {
+	Records []audit.Record `json:"records"`
+}
+
+func makeGetFeatureFlagEndpoint(svc service.Service, m *metrics.Metrics) kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(GetFeatureFlagRequest)
+		val, _ := svc.GetFeatureFlag(ctx, req.Key, req.Context)
+		if m != nil {
+			m.EvaluationsTotal.WithLabelValues(req.Key, fmt.Sprintf("%v", val)).Inc()
+		}
+		return GetFeatureFlagResponse{Value: val}, nil
+	}
+}
+
+func makeSetFeatureFlagEndpoint(svc service.Service) kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(SetFeatureFlagRequest)
+		err := svc.SetFeatureFlag(ctx, req.Key, req.Flag)
+		if err != nil {
+			return SetFeatureFlagResponse{Success: false}, err
+		}
+		return SetFeatureFlagResponse{Success: true}, nil
+	}
+}
+
+// makeEvaluateEndpoint mirrors makeGetFeatureFlagEndpoint; it exists as a
+// distinct, more discoverable name for SDKs that evaluate a single flag
+// against a context, and takes and returns exactly what /get does.
+func makeEvaluateEndpoint(svc service.Service, m *metrics.Metrics) kitendpoint.Endpoint {
+	return makeGetFeatureFlagEndpoint(svc, m)
+}
+
+func makeEvaluateAllEndpoint(svc service.Service, m *metrics.Metrics) kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(EvaluateAllRequest)
+		all, err := svc.ListFlags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]interface{}, len(all))
+		for key, f := range all {
+			val := f.Evaluate(key, req.Context)
+			values[key] = val
+			if m != nil {
+				m.EvaluationsTotal.WithLabelValues(key, fmt.Sprintf("%v", val)).Inc()
+			}
+		}
+		return EvaluateAllResponse{Values: values}, nil
+	}
+}
+
+func makeListFlagsEndpoint(svc service.Service) kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		all, err := svc.ListFlags(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return ListFlagsResponse{Flags: all}, nil
+	}
+}
+
+func makeAuditEndpoint(svc service.Service) kitendpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req := request.(AuditRequest)
+		records, err := svc.QueryAudit(ctx, req.Key)
+		if err != nil {
+			return nil, err
+		}
+		return AuditResponse{Records: records}, nil
+	}
+}
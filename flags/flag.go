@@ -0,0 +1,138 @@
+// Package flags defines the structured Flag representation and the rule
+// engine used to resolve a flag to a variation for a given evaluation
+// context.
+package flags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Context is the set of attributes a caller supplies when evaluating a
+// flag, e.g. userID, email, country, plan.
+type Context map[string]interface{}
+
+// Flag is a feature flag's full configuration: a default variation plus an
+// ordered list of targeting rules. Evaluation walks Rules in order and
+// returns the first match's Variation, falling back to Default.
+type Flag struct {
+	Default interface{} `json:"default"`
+	Rules   []Rule      `json:"rules,omitempty"`
+}
+
+// Rule pairs a Predicate with the Variation to return when it matches.
+type Rule struct {
+	Predicate Predicate   `json:"predicate"`
+	Variation interface{} `json:"variation"`
+}
+
+// Op names the kind of comparison a Predicate performs.
+type Op string
+
+const (
+	OpEquals        Op = "eq"
+	OpIn            Op = "in"
+	OpRegex         Op = "regex"
+	OpSemverInRange Op = "semver"
+	OpPercentage    Op = "percentage"
+)
+
+// Predicate matches an evaluation Context against Attribute using Op and
+// Value. Attribute is unused for OpPercentage, which instead buckets the
+// context's userID and matches when the bucket is below Value (a threshold
+// in 0..10000).
+type Predicate struct {
+	Attribute string      `json:"attribute,omitempty"`
+	Op        Op          `json:"op"`
+	Value     interface{} `json:"value,omitempty"`
+}
+
+// Evaluate resolves f to a variation for ctx: the Variation of the first
+// matching Rule, or Default if none match.
+func (f Flag) Evaluate(key string, ctx Context) interface{} {
+	for _, rule := range f.Rules {
+		if rule.Predicate.match(key, ctx) {
+			return rule.Variation
+		}
+	}
+	return f.Default
+}
+
+func (p Predicate) match(flagKey string, ctx Context) bool {
+	if p.Op == OpPercentage {
+		return bucket(fmt.Sprint(ctx["userID"]), flagKey) < percentageThreshold(p.Value)
+	}
+
+	actual, ok := ctx[p.Attribute]
+	if !ok {
+		return false
+	}
+
+	switch p.Op {
+	case OpEquals:
+		return fmt.Sprint(actual) == fmt.Sprint(p.Value)
+	case OpIn:
+		values, ok := p.Value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if fmt.Sprint(v) == fmt.Sprint(actual) {
+				return true
+			}
+		}
+		return false
+	case OpRegex:
+		pattern, ok := p.Value.(string)
+		if !ok {
+			return false
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(fmt.Sprint(actual))
+	case OpSemverInRange:
+		constraint, ok := p.Value.(string)
+		if !ok {
+			return false
+		}
+		c, err := semver.NewConstraint(constraint)
+		if err != nil {
+			return false
+		}
+		v, err := semver.NewVersion(fmt.Sprint(actual))
+		if err != nil {
+			return false
+		}
+		return c.Check(v)
+	default:
+		return false
+	}
+}
+
+// percentageThreshold normalizes the rule's configured threshold (0..10000)
+// regardless of whether it was decoded as an int or a float64 (the latter is
+// what encoding/json produces for untyped numbers).
+func percentageThreshold(v interface{}) int {
+	switch t := v.(type) {
+	case float64:
+		return int(t)
+	case int:
+		return t
+	default:
+		return 0
+	}
+}
+
+// bucket consistently hashes userID+flagKey into 0..9999 so that the same
+// user always buckets the same way for a given flag, and different flags
+// bucket the same user independently.
+func bucket(userID, flagKey string) int {
+	h := fnv.New32a()
+	h.Write([]byte(userID + flagKey))
+	return int(h.Sum32() % 10000)
+}
@@ -0,0 +1,144 @@
+package flags
+
+import "testing"
+
+func TestEvaluateDefault(t *testing.T) {
+	f := Flag{Default: "off"}
+	if got := f.Evaluate("my-flag", Context{}); got != "off" {
+		t.Fatalf("Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluateEquals(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "country", Op: OpEquals, Value: "US"}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"country": "US"}); got != "on" {
+		t.Fatalf("matching country: Evaluate() = %v, want %q", got, "on")
+	}
+	if got := f.Evaluate("k", Context{"country": "DE"}); got != "off" {
+		t.Fatalf("non-matching country: Evaluate() = %v, want %q", got, "off")
+	}
+	if got := f.Evaluate("k", Context{}); got != "off" {
+		t.Fatalf("missing attribute: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluateIn(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "plan", Op: OpIn, Value: []interface{}{"pro", "enterprise"}}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"plan": "pro"}); got != "on" {
+		t.Fatalf("plan in list: Evaluate() = %v, want %q", got, "on")
+	}
+	if got := f.Evaluate("k", Context{"plan": "free"}); got != "off" {
+		t.Fatalf("plan not in list: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluateRegex(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "email", Op: OpRegex, Value: `@example\.com$`}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"email": "a@example.com"}); got != "on" {
+		t.Fatalf("matching email: Evaluate() = %v, want %q", got, "on")
+	}
+	if got := f.Evaluate("k", Context{"email": "a@other.com"}); got != "off" {
+		t.Fatalf("non-matching email: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluateRegexInvalidPatternDoesNotMatch(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "email", Op: OpRegex, Value: `(`}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"email": "a@example.com"}); got != "off" {
+		t.Fatalf("invalid pattern: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluateSemverInRange(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "app_version", Op: OpSemverInRange, Value: ">= 2.0.0"}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"app_version": "2.5.0"}); got != "on" {
+		t.Fatalf("in range: Evaluate() = %v, want %q", got, "on")
+	}
+	if got := f.Evaluate("k", Context{"app_version": "1.9.0"}); got != "off" {
+		t.Fatalf("out of range: Evaluate() = %v, want %q", got, "off")
+	}
+	if got := f.Evaluate("k", Context{"app_version": "not-a-version"}); got != "off" {
+		t.Fatalf("unparseable version: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluatePercentageIsStableForSameUser(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Op: OpPercentage, Value: float64(10000)}, Variation: "on"},
+		},
+	}
+	got1 := f.Evaluate("my-flag", Context{"userID": "alice"})
+	got2 := f.Evaluate("my-flag", Context{"userID": "alice"})
+	if got1 != got2 {
+		t.Fatalf("bucketing not stable: got %v then %v", got1, got2)
+	}
+	if got1 != "on" {
+		t.Fatalf("100%% threshold: Evaluate() = %v, want %q", got1, "on")
+	}
+}
+
+func TestEvaluatePercentageZeroThresholdNeverMatches(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Op: OpPercentage, Value: float64(0)}, Variation: "on"},
+		},
+	}
+	if got := f.Evaluate("my-flag", Context{"userID": "anyone"}); got != "off" {
+		t.Fatalf("0%% threshold: Evaluate() = %v, want %q", got, "off")
+	}
+}
+
+func TestEvaluatePercentageMissingUserIDIsStillDeterministic(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Op: OpPercentage, Value: float64(10000)}, Variation: "on"},
+		},
+	}
+	// No userID in ctx: bucket() hashes fmt.Sprint(nil) == "<nil>", which is
+	// still a valid (if shared-by-everyone) bucket rather than a crash.
+	if got := f.Evaluate("my-flag", Context{}); got != "on" {
+		t.Fatalf("missing userID at 100%% threshold: Evaluate() = %v, want %q", got, "on")
+	}
+}
+
+func TestEvaluateRulesAreCheckedInOrder(t *testing.T) {
+	f := Flag{
+		Default: "off",
+		Rules: []Rule{
+			{Predicate: Predicate{Attribute: "country", Op: OpEquals, Value: "US"}, Variation: "first"},
+			{Predicate: Predicate{Attribute: "country", Op: OpEquals, Value: "US"}, Variation: "second"},
+		},
+	}
+	if got := f.Evaluate("k", Context{"country": "US"}); got != "first" {
+		t.Fatalf("Evaluate() = %v, want %q (first matching rule wins)", got, "first")
+	}
+}
@@ -1,122 +1,189 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
+	"flag"
+	"net"
 	"net/http"
 	"os"
-	"sync"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
 
-	"github.com/go-kit/kit/endpoint"
-	kitHttp "github.com/go-kit/kit/transport/http"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
+	"google.golang.org/grpc"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/auth"
+	"github.com/build-a-thing-and-show/feature-flag-service/endpoint"
+	"github.com/build-a-thing-and-show/feature-flag-service/metrics"
+	"github.com/build-a-thing-and-show/feature-flag-service/pb"
+	"github.com/build-a-thing-and-show/feature-flag-service/registry"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+	"github.com/build-a-thing-and-show/feature-flag-service/storage"
+	grpctransport "github.com/build-a-thing-and-show/feature-flag-service/transport/grpc"
+	httptransport "github.com/build-a-thing-and-show/feature-flag-service/transport/http"
+	"github.com/build-a-thing-and-show/feature-flag-service/wal"
 )
 
-// FeatureFlagService defines the interface for managing feature flags
-type FeatureFlagService interface {
-	GetFeatureFlag(ctx context.Context, key string) (bool, error)
-	SetFeatureFlag(ctx context.Context, key string, value bool) error
-}
+func main() {
+	var (
+		storageBackend = flag.String("storage", "bolt", "storage backend to use: bolt or redis")
+		storageAddr    = flag.String("storage-addr", "featureflags.db", "storage address: bolt file path, or redis host:port")
+		walPath        = flag.String("wal", "featureflags.wal", "path to the write-ahead log file")
+		httpAddr       = flag.String("http-addr", ":10001", "address to serve the HTTP/JSON transport on")
+		grpcAddr       = flag.String("grpc-addr", ":10002", "address to serve the gRPC transport on")
+		adminAddr      = flag.String("admin-addr", ":10003", "address to serve /metrics on, separate from flag traffic")
+
+		registryBackend = flag.String("registry", "static", "service discovery backend to register with: consul, etcd, or static")
+		advertiseHost   = flag.String("advertise-host", "localhost", "host other instances/clients can reach this instance at")
+		consulAddr      = flag.String("consul-addr", "127.0.0.1:8500", "Consul agent address, used when --registry=consul")
+		etcdEndpoints   = flag.String("etcd-endpoints", "127.0.0.1:2379", "comma-separated etcd endpoints, used when --registry=etcd")
+		serviceName     = flag.String("service-name", "feature-flag-service", "name to register this instance under")
+
+		jwksURL = flag.String("jwks-url", "", "JWKS URL to validate bearer JWTs against; auth is disabled (all endpoints open) when unset")
+
+		auditSinkKind = flag.String("audit-sink", "stdout", "where mutation audit records are written: stdout, file, or kafka")
+		auditFilePath = flag.String("audit-file", "featureflags.audit", "audit log path, used when --audit-sink=file")
+		kafkaBrokers  = flag.String("audit-kafka-brokers", "127.0.0.1:9092", "comma-separated Kafka brokers, used when --audit-sink=kafka")
+		kafkaTopic    = flag.String("audit-kafka-topic", "featureflags.audit", "Kafka topic, used when --audit-sink=kafka")
+	)
+	flag.Parse()
 
-// featureFlagService is a concrete implementation of FeatureFlagService
-type featureFlagService struct {
-	flags map[string]bool
-	mu    sync.RWMutex
-}
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout))
+	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 
-func (s *featureFlagService) GetFeatureFlag(ctx context.Context, key string) (bool, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-	val, exists := s.flags[key]
-	if !exists {
-		return false, nil
+	store, err := storage.New(*storageBackend, *storageAddr)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to open storage backend", "err", err)
+		os.Exit(1)
 	}
-	return val, nil
-}
 
-func (s *featureFlagService) SetFeatureFlag(ctx context.Context, key string, value bool) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	s.flags[key] = value
-	return nil
-}
-
-// request and response types
-type getFeatureFlagRequest struct {
-	Key string `json:"key"`
-}
+	writeAheadLog, err := wal.Open(*walPath)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to open write-ahead log", "err", err)
+		os.Exit(1)
+	}
 
-type getFeatureFlagResponse struct {
-	Value bool `json:"value"`
-}
+	auditSink, err := newAuditSink(*auditSinkKind, *auditFilePath, *kafkaBrokers, *kafkaTopic)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to build audit sink", "sink", *auditSinkKind, "err", err)
+		os.Exit(1)
+	}
 
-type setFeatureFlagRequest struct {
-	Key   string `json:"key"`
-	Value bool   `json:"value"`
-}
+	m := metrics.New()
 
-type setFeatureFlagResponse struct {
-	Success bool `json:"success"`
-}
-
-// Endpoints
-func makeGetFeatureFlagEndpoint(svc FeatureFlagService) endpoint.Endpoint {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(getFeatureFlagRequest)
-		val, _ := svc.GetFeatureFlag(ctx, req.Key)
-		return getFeatureFlagResponse{Value: val}, nil
+	svc, err := service.New(store, writeAheadLog, *walPath, auditSink, m)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to replay write-ahead log", "err", err)
+		os.Exit(1)
 	}
-}
 
-func makeSetFeatureFlagEndpoint(svc FeatureFlagService) endpoint.Endpoint {
-	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		req := request.(setFeatureFlagRequest)
-		svc.SetFeatureFlag(ctx, req.Key, req.Value)
-		return setFeatureFlagResponse{Success: true}, nil
+	var authenticator *auth.Authenticator
+	if *jwksURL != "" {
+		authenticator, err = auth.NewAuthenticator(*jwksURL)
+		if err != nil {
+			level.Error(logger).Log("msg", "failed to build authenticator", "jwks_url", *jwksURL, "err", err)
+			os.Exit(1)
+		}
 	}
-}
-
-func main() {
-	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout))
-	logger = log.With(logger, "ts", log.DefaultTimestampUTC)
 
-	svc := &featureFlagService{flags: make(map[string]bool)}
-	getFeatureFlagHandler := kitHttp.NewServer(
-		makeGetFeatureFlagEndpoint(svc),
-		decodeGetFeatureFlagRequest,
-		encodeResponse,
-	)
+	endpoints := endpoint.MakeServerEndpoints(svc, authenticator, m)
 
-	setFeatureFlagHandler := kitHttp.NewServer(
-		makeSetFeatureFlagEndpoint(svc),
-		decodeSetFeatureFlagRequest,
-		encodeResponse,
-	)
+	go func() {
+		level.Info(logger).Log("msg", "starting admin server", "addr", *adminAddr)
+		if err := http.ListenAndServe(*adminAddr, httptransport.NewAdminHandler()); err != nil {
+			level.Error(logger).Log("msg", "admin server stopped", "err", err)
+			os.Exit(1)
+		}
+	}()
 
-	http.Handle("/get", getFeatureFlagHandler)
-	http.Handle("/set", setFeatureFlagHandler)
+	grpcListener, err := net.Listen("tcp", *grpcAddr)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to listen for gRPC", "err", err)
+		os.Exit(1)
+	}
+	var grpcOptions []grpc.ServerOption
+	if authenticator != nil {
+		grpcOptions = append(grpcOptions,
+			grpc.UnaryInterceptor(authenticator.UnaryServerInterceptor()),
+			grpc.StreamInterceptor(authenticator.StreamServerInterceptor()),
+		)
+	}
+	grpcServer := grpc.NewServer(grpcOptions...)
+	pb.RegisterFeatureFlagServiceServer(grpcServer, grpctransport.NewServer(svc, endpoints, authenticator))
+
+	go func() {
+		level.Info(logger).Log("msg", "starting gRPC server", "addr", *grpcAddr)
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			level.Error(logger).Log("msg", "gRPC server stopped", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	httpPort, err := portOf(*httpAddr)
+	if err != nil {
+		level.Error(logger).Log("msg", "invalid --http-addr", "err", err)
+		os.Exit(1)
+	}
 
-	level.Info(logger).Log("msg", "Starting server on port 10001")
-	http.ListenAndServe(":10001", nil)
+	registrar, err := newRegistrar(*registryBackend, *consulAddr, *etcdEndpoints, *serviceName, *advertiseHost, httpPort, logger)
+	if err != nil {
+		level.Error(logger).Log("msg", "failed to build registrar", "registry", *registryBackend, "err", err)
+		os.Exit(1)
+	}
+	registrar.Register()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		<-sigCh
+		level.Info(logger).Log("msg", "received shutdown signal, deregistering")
+		registrar.Deregister()
+		os.Exit(0)
+	}()
+
+	level.Info(logger).Log("msg", "starting HTTP server", "addr", *httpAddr)
+	http.ListenAndServe(*httpAddr, httptransport.NewHandler(svc, endpoints, authenticator))
 }
 
-func decodeGetFeatureFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var request getFeatureFlagRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		return nil, err
+// newAuditSink builds the audit.Sink selected by kind. filePath, brokers,
+// and topic are only consulted for their respective kinds.
+func newAuditSink(kind, filePath, brokers, topic string) (audit.Sink, error) {
+	switch kind {
+	case "stdout", "":
+		return audit.NewStdoutSink(os.Stdout), nil
+	case "file":
+		return audit.NewFileSink(filePath)
+	case "kafka":
+		return audit.NewKafkaSink(strings.Split(brokers, ","), topic), nil
+	default:
+		return nil, audit.ErrUnknownBackend(kind)
 	}
-	return request, nil
 }
 
-func decodeSetFeatureFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
-	var request setFeatureFlagRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		return nil, err
+// newRegistrar builds the Registrar selected by backend. consulAddr and
+// etcdEndpoints are only consulted for their respective backends.
+func newRegistrar(backend, consulAddr, etcdEndpoints, serviceName, host string, port int, logger log.Logger) (registry.Registrar, error) {
+	switch backend {
+	case "consul":
+		return registry.NewConsul(consulAddr, serviceName, host, port, "/health", logger)
+	case "etcd":
+		key := "/services/" + serviceName + "/" + host + ":" + strconv.Itoa(port)
+		value := host + ":" + strconv.Itoa(port)
+		return registry.NewEtcd(strings.Split(etcdEndpoints, ","), key, value, logger)
+	case "static", "":
+		return registry.NewStatic(), nil
+	default:
+		return nil, registry.ErrUnknownBackend(backend)
 	}
-	return request, nil
 }
 
-func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
-	return json.NewEncoder(w).Encode(response)
+func portOf(addr string) (int, error) {
+	_, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(portStr)
 }
@@ -0,0 +1,38 @@
+// Package metrics exposes the Prometheus collectors the feature-flag
+// service reports: request latency per endpoint, evaluations per flag and
+// resolved variation, and the number of connected stream subscribers.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Metrics holds every Prometheus collector the service reports.
+type Metrics struct {
+	RequestLatency    *prometheus.HistogramVec
+	EvaluationsTotal  *prometheus.CounterVec
+	StreamSubscribers prometheus.Gauge
+}
+
+// New registers and returns the service's Prometheus collectors against the
+// default registry, which /metrics serves.
+func New() *Metrics {
+	return &Metrics{
+		RequestLatency: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "featureflags",
+			Name:      "request_duration_seconds",
+			Help:      "Request latency by endpoint.",
+		}, []string{"endpoint"}),
+		EvaluationsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "featureflags",
+			Name:      "evaluations_total",
+			Help:      "Flag evaluations, by flag key and resolved variation.",
+		}, []string{"flag", "variation"}),
+		StreamSubscribers: promauto.NewGauge(prometheus.GaugeOpts{
+			Namespace: "featureflags",
+			Name:      "stream_subscribers",
+			Help:      "Number of currently connected flag-change subscribers (/stream, /ws, and gRPC WatchFlags).",
+		}),
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+)
+
+// InstrumentingMiddleware returns an endpoint.Middleware that records how
+// long each call to the wrapped endpoint takes in m.RequestLatency, labeled
+// by name.
+func InstrumentingMiddleware(name string, m *Metrics) kitendpoint.Middleware {
+	return func(next kitendpoint.Endpoint) kitendpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			defer func(begin time.Time) {
+				m.RequestLatency.WithLabelValues(name).Observe(time.Since(begin).Seconds())
+			}(time.Now())
+			return next(ctx, request)
+		}
+	}
+}
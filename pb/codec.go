@@ -0,0 +1,33 @@
+package pb
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// jsonCodec implements grpc's encoding.Codec using encoding/json instead of
+// the real protobuf wire format. This package's message types are plain Go
+// structs, not proto.Message implementations, so grpc's built-in "proto"
+// codec can't marshal them (it rejects anything without Reset/ProtoReflect
+// with "message is *pb.Foo, want proto.Message"). Registering this codec
+// under the same name, "proto" — what every call uses unless a
+// CallContentSubtype says otherwise — replaces it process-wide for both the
+// client and server built from this package, so RPCs actually work.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return "proto"
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
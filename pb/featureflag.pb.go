@@ -0,0 +1,129 @@
+// Package pb defines the gRPC transport's message and service types for
+// featureflag.proto. There is no protoc in this build's toolchain, so these
+// are hand-written structs shaped like protoc-gen-go output rather than
+// genuine generated proto.Message implementations; codec.go registers a
+// JSON-based grpc.Codec under the "proto" name so these plain structs
+// marshal correctly over a real grpc.Server/ClientConn instead of failing
+// the standard protobuf codec's proto.Message check.
+// source: featureflag.proto
+
+package pb
+
+type GetFeatureFlagRequest struct {
+	Key         string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ContextJson string `protobuf:"bytes,2,opt,name=context_json,json=contextJson,proto3" json:"context_json,omitempty"`
+}
+
+func (m *GetFeatureFlagRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetFeatureFlagRequest) GetContextJson() string {
+	if m != nil {
+		return m.ContextJson
+	}
+	return ""
+}
+
+type GetFeatureFlagResponse struct {
+	ValueJson string `protobuf:"bytes,1,opt,name=value_json,json=valueJson,proto3" json:"value_json,omitempty"`
+}
+
+func (m *GetFeatureFlagResponse) GetValueJson() string {
+	if m != nil {
+		return m.ValueJson
+	}
+	return ""
+}
+
+type SetFeatureFlagRequest struct {
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	FlagJson string `protobuf:"bytes,2,opt,name=flag_json,json=flagJson,proto3" json:"flag_json,omitempty"`
+}
+
+func (m *SetFeatureFlagRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *SetFeatureFlagRequest) GetFlagJson() string {
+	if m != nil {
+		return m.FlagJson
+	}
+	return ""
+}
+
+type SetFeatureFlagResponse struct {
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+}
+
+func (m *SetFeatureFlagResponse) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+type ListFlagsRequest struct{}
+
+type ListFlagsResponse struct {
+	FlagsJson map[string]string `protobuf:"bytes,1,rep,name=flags_json,json=flagsJson,proto3" json:"flags_json,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *ListFlagsResponse) GetFlagsJson() map[string]string {
+	if m != nil {
+		return m.FlagsJson
+	}
+	return nil
+}
+
+type WatchFlagsRequest struct {
+	Keys []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+}
+
+func (m *WatchFlagsRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type FlagEvent struct {
+	Key       string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	ValueJson string `protobuf:"bytes,2,opt,name=value_json,json=valueJson,proto3" json:"value_json,omitempty"`
+	Version   int64  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	UnixTs    int64  `protobuf:"varint,4,opt,name=unix_ts,json=unixTs,proto3" json:"unix_ts,omitempty"`
+}
+
+func (m *FlagEvent) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *FlagEvent) GetValueJson() string {
+	if m != nil {
+		return m.ValueJson
+	}
+	return ""
+}
+
+func (m *FlagEvent) GetVersion() int64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *FlagEvent) GetUnixTs() int64 {
+	if m != nil {
+		return m.UnixTs
+	}
+	return 0
+}
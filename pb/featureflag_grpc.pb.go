@@ -0,0 +1,209 @@
+// Hand-written client/server stubs shaped like protoc-gen-go-grpc output
+// (see featureflag.pb.go's doc comment and codec.go for why these aren't
+// genuine protoc output).
+// source: featureflag.proto
+
+package pb
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	FeatureFlagService_GetFeatureFlag_FullMethodName = "/pb.FeatureFlagService/GetFeatureFlag"
+	FeatureFlagService_SetFeatureFlag_FullMethodName = "/pb.FeatureFlagService/SetFeatureFlag"
+	FeatureFlagService_ListFlags_FullMethodName      = "/pb.FeatureFlagService/ListFlags"
+	FeatureFlagService_WatchFlags_FullMethodName     = "/pb.FeatureFlagService/WatchFlags"
+)
+
+// FeatureFlagServiceClient is the client API for FeatureFlagService.
+type FeatureFlagServiceClient interface {
+	GetFeatureFlag(ctx context.Context, in *GetFeatureFlagRequest, opts ...grpc.CallOption) (*GetFeatureFlagResponse, error)
+	SetFeatureFlag(ctx context.Context, in *SetFeatureFlagRequest, opts ...grpc.CallOption) (*SetFeatureFlagResponse, error)
+	ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error)
+	WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (FeatureFlagService_WatchFlagsClient, error)
+}
+
+type featureFlagServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFeatureFlagServiceClient(cc grpc.ClientConnInterface) FeatureFlagServiceClient {
+	return &featureFlagServiceClient{cc}
+}
+
+func (c *featureFlagServiceClient) GetFeatureFlag(ctx context.Context, in *GetFeatureFlagRequest, opts ...grpc.CallOption) (*GetFeatureFlagResponse, error) {
+	out := new(GetFeatureFlagResponse)
+	if err := c.cc.Invoke(ctx, FeatureFlagService_GetFeatureFlag_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) SetFeatureFlag(ctx context.Context, in *SetFeatureFlagRequest, opts ...grpc.CallOption) (*SetFeatureFlagResponse, error) {
+	out := new(SetFeatureFlagResponse)
+	if err := c.cc.Invoke(ctx, FeatureFlagService_SetFeatureFlag_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) ListFlags(ctx context.Context, in *ListFlagsRequest, opts ...grpc.CallOption) (*ListFlagsResponse, error) {
+	out := new(ListFlagsResponse)
+	if err := c.cc.Invoke(ctx, FeatureFlagService_ListFlags_FullMethodName, in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *featureFlagServiceClient) WatchFlags(ctx context.Context, in *WatchFlagsRequest, opts ...grpc.CallOption) (FeatureFlagService_WatchFlagsClient, error) {
+	stream, err := c.cc.(grpc.ClientConnInterface).NewStream(ctx, &FeatureFlagService_ServiceDesc.Streams[0], FeatureFlagService_WatchFlags_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &featureFlagServiceWatchFlagsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FeatureFlagService_WatchFlagsClient interface {
+	Recv() (*FlagEvent, error)
+	grpc.ClientStream
+}
+
+type featureFlagServiceWatchFlagsClient struct {
+	grpc.ClientStream
+}
+
+func (x *featureFlagServiceWatchFlagsClient) Recv() (*FlagEvent, error) {
+	m := new(FlagEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FeatureFlagServiceServer is the server API for FeatureFlagService.
+type FeatureFlagServiceServer interface {
+	GetFeatureFlag(context.Context, *GetFeatureFlagRequest) (*GetFeatureFlagResponse, error)
+	SetFeatureFlag(context.Context, *SetFeatureFlagRequest) (*SetFeatureFlagResponse, error)
+	ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error)
+	WatchFlags(*WatchFlagsRequest, FeatureFlagService_WatchFlagsServer) error
+}
+
+// UnimplementedFeatureFlagServiceServer can be embedded to have forward
+// compatible implementations.
+type UnimplementedFeatureFlagServiceServer struct{}
+
+func (UnimplementedFeatureFlagServiceServer) GetFeatureFlag(context.Context, *GetFeatureFlagRequest) (*GetFeatureFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFeatureFlag not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) SetFeatureFlag(context.Context, *SetFeatureFlagRequest) (*SetFeatureFlagResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetFeatureFlag not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) ListFlags(context.Context, *ListFlagsRequest) (*ListFlagsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFlags not implemented")
+}
+func (UnimplementedFeatureFlagServiceServer) WatchFlags(*WatchFlagsRequest, FeatureFlagService_WatchFlagsServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchFlags not implemented")
+}
+
+func RegisterFeatureFlagServiceServer(s grpc.ServiceRegistrar, srv FeatureFlagServiceServer) {
+	s.RegisterService(&FeatureFlagService_ServiceDesc, srv)
+}
+
+func _FeatureFlagService_GetFeatureFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFeatureFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).GetFeatureFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: FeatureFlagService_GetFeatureFlag_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).GetFeatureFlag(ctx, req.(*GetFeatureFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_SetFeatureFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetFeatureFlagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).SetFeatureFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: FeatureFlagService_SetFeatureFlag_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).SetFeatureFlag(ctx, req.(*SetFeatureFlagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_ListFlags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFlagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeatureFlagServiceServer).ListFlags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: FeatureFlagService_ListFlags_FullMethodName}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeatureFlagServiceServer).ListFlags(ctx, req.(*ListFlagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeatureFlagService_WatchFlags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchFlagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FeatureFlagServiceServer).WatchFlags(m, &featureFlagServiceWatchFlagsServer{stream})
+}
+
+type FeatureFlagService_WatchFlagsServer interface {
+	Send(*FlagEvent) error
+	grpc.ServerStream
+}
+
+type featureFlagServiceWatchFlagsServer struct {
+	grpc.ServerStream
+}
+
+func (x *featureFlagServiceWatchFlagsServer) Send(m *FlagEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// FeatureFlagService_ServiceDesc is the grpc.ServiceDesc for
+// FeatureFlagService, used by RegisterFeatureFlagServiceServer and
+// NewFeatureFlagServiceClient.
+var FeatureFlagService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.FeatureFlagService",
+	HandlerType: (*FeatureFlagServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetFeatureFlag", Handler: _FeatureFlagService_GetFeatureFlag_Handler},
+		{MethodName: "SetFeatureFlag", Handler: _FeatureFlagService_SetFeatureFlag_Handler},
+		{MethodName: "ListFlags", Handler: _FeatureFlagService_ListFlags_Handler},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchFlags",
+			Handler:       _FeatureFlagService_WatchFlags_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "featureflag.proto",
+}
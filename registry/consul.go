@@ -0,0 +1,51 @@
+package registry
+
+import (
+	"fmt"
+
+	kitconsul "github.com/go-kit/kit/sd/consul"
+	"github.com/go-kit/log"
+	consulapi "github.com/hashicorp/consul/api"
+)
+
+// consulRegistrar registers the instance with a Consul agent, including an
+// HTTP health check Consul polls to decide whether the instance is eligible
+// for discovery.
+type consulRegistrar struct {
+	registrar *kitconsul.Registrar
+}
+
+// NewConsul registers serviceName at host:port with the Consul agent at
+// consulAddr, health-checked via an HTTP GET of healthCheckPath.
+func NewConsul(consulAddr, serviceName, host string, port int, healthCheckPath string, logger log.Logger) (Registrar, error) {
+	client, err := consulapi.NewClient(&consulapi.Config{Address: consulAddr})
+	if err != nil {
+		return nil, err
+	}
+	sdClient := kitconsul.NewClient(client)
+
+	registration := &consulapi.AgentServiceRegistration{
+		ID:      fmt.Sprintf("%s-%s-%d", serviceName, host, port),
+		Name:    serviceName,
+		Address: host,
+		Port:    port,
+		Check: &consulapi.AgentServiceCheck{
+			HTTP:                           fmt.Sprintf("http://%s:%d%s", host, port, healthCheckPath),
+			Interval:                       "10s",
+			Timeout:                        "2s",
+			DeregisterCriticalServiceAfter: "1m",
+		},
+	}
+
+	return &consulRegistrar{registrar: kitconsul.NewRegistrar(sdClient, registration, logger)}, nil
+}
+
+func (r *consulRegistrar) Register() error {
+	r.registrar.Register()
+	return nil
+}
+
+func (r *consulRegistrar) Deregister() error {
+	r.registrar.Deregister()
+	return nil
+}
@@ -0,0 +1,41 @@
+package registry
+
+import (
+	"context"
+
+	etcdv3 "github.com/go-kit/kit/sd/etcdv3"
+	"github.com/go-kit/log"
+)
+
+// etcdRegistrar registers the instance as a key/value pair under serviceKey,
+// refreshed via a lease so the entry disappears if the instance stops
+// renewing it.
+type etcdRegistrar struct {
+	registrar *etcdv3.Registrar
+}
+
+// NewEtcd registers serviceValue (typically "host:port") under serviceKey
+// with the etcd cluster at endpoints.
+func NewEtcd(endpoints []string, serviceKey, serviceValue string, logger log.Logger) (Registrar, error) {
+	client, err := etcdv3.NewClient(context.Background(), endpoints, etcdv3.ClientOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	service := etcdv3.Service{
+		Key:   serviceKey,
+		Value: serviceValue,
+	}
+
+	return &etcdRegistrar{registrar: etcdv3.NewRegistrar(client, service, logger)}, nil
+}
+
+func (r *etcdRegistrar) Register() error {
+	r.registrar.Register()
+	return nil
+}
+
+func (r *etcdRegistrar) Deregister() error {
+	r.registrar.Deregister()
+	return nil
+}
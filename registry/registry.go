@@ -0,0 +1,21 @@
+// Package registry registers this feature-flag instance with a service
+// discovery backend on startup, and deregisters it on shutdown, so that
+// client.NewDiscovered can find healthy replicas without static
+// configuration.
+package registry
+
+// Registrar registers and deregisters a single service instance with a
+// discovery backend. Implementations should be safe to call Deregister on
+// more than once (e.g. from both a SIGTERM handler and a deferred cleanup).
+type Registrar interface {
+	Register() error
+	Deregister() error
+}
+
+// ErrUnknownBackend is returned when a caller selects a registry backend
+// name that does not match a known implementation.
+type ErrUnknownBackend string
+
+func (e ErrUnknownBackend) Error() string {
+	return "registry: unknown backend " + string(e)
+}
@@ -0,0 +1,13 @@
+package registry
+
+// Static is a no-op Registrar for single-instance deployments or local
+// development, where there is no discovery backend to register with.
+type Static struct{}
+
+// NewStatic returns a Registrar whose Register/Deregister are no-ops.
+func NewStatic() Registrar {
+	return Static{}
+}
+
+func (Static) Register() error   { return nil }
+func (Static) Deregister() error { return nil }
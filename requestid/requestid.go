@@ -0,0 +1,72 @@
+// Package requestid threads a request ID through context so that every
+// layer — transports, middleware, the audit log — can tag its work with the
+// same identifier without each one inventing its own scheme.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// HeaderName is the inbound header/metadata key transports extract a
+// caller-supplied request ID from, if present.
+const HeaderName = "X-Request-Id"
+
+type contextKey struct{}
+
+// With returns a context carrying id.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From returns the request ID carried by ctx, if any.
+func From(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+	return id, ok
+}
+
+// FromOrNew returns the request ID carried by ctx, generating and returning
+// a new one if ctx doesn't carry one (e.g. a transport that doesn't
+// propagate a request ID header, or a call made directly in tests).
+func FromOrNew(ctx context.Context) string {
+	if id, ok := From(ctx); ok {
+		return id
+	}
+	return New()
+}
+
+// HTTPToContext is a go-kit transport/http.RequestFunc that extracts
+// HeaderName, if present, into the request context, so FromOrNew later in
+// the call chain can correlate its audit record with the request that
+// produced it instead of always generating a fresh ID.
+func HTTPToContext() func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		if id := r.Header.Get(HeaderName); id != "" {
+			return With(ctx, id)
+		}
+		return ctx
+	}
+}
+
+// GRPCToContext is a go-kit transport/grpc.ServerRequestFunc that extracts
+// HeaderName from the inbound metadata, mirroring HTTPToContext for the
+// gRPC transport.
+func GRPCToContext(ctx context.Context, md metadata.MD) context.Context {
+	if values := md.Get(HeaderName); len(values) > 0 && values[0] != "" {
+		return With(ctx, values[0])
+	}
+	return ctx
+}
+
+// New generates a random request ID.
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
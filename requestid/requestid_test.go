@@ -0,0 +1,46 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestHTTPToContextExtractsHeader(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/get", nil)
+	r.Header.Set(HeaderName, "req-123")
+
+	ctx := HTTPToContext()(context.Background(), r)
+
+	id, ok := From(ctx)
+	if !ok || id != "req-123" {
+		t.Fatalf("From(ctx) = %q, %v; want %q, true", id, ok, "req-123")
+	}
+}
+
+func TestHTTPToContextLeavesContextAloneWhenHeaderMissing(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/get", nil)
+
+	ctx := HTTPToContext()(context.Background(), r)
+
+	if _, ok := From(ctx); ok {
+		t.Fatal("From(ctx) reported a request ID when no header was sent")
+	}
+	if id := FromOrNew(ctx); id == "" {
+		t.Fatal("FromOrNew(ctx) returned an empty ID")
+	}
+}
+
+func TestGRPCToContextExtractsMetadata(t *testing.T) {
+	md := metadata.Pairs(HeaderName, "req-456")
+
+	ctx := GRPCToContext(context.Background(), md)
+
+	id, ok := From(ctx)
+	if !ok || id != "req-456" {
+		t.Fatalf("From(ctx) = %q, %v; want %q, true", id, ok, "req-456")
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/metrics"
+)
+
+// eventHistoryLimit bounds how many past mutations Subscribe can replay via
+// a resume-from-version cursor; older events are dropped.
+const eventHistoryLimit = 1000
+
+// Event is published on every successful SetFeatureFlag.
+type Event struct {
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value"`
+	Version int64       `json:"version"`
+	TS      time.Time   `json:"ts"`
+}
+
+// eventBus fans out published events to every active subscriber, and keeps a
+// bounded backlog so a new subscriber can resume from a given version
+// instead of missing mutations made while it was reconnecting.
+type eventBus struct {
+	mu      sync.Mutex
+	version int64
+	history []Event
+	subs    map[chan Event]struct{}
+
+	m *metrics.Metrics
+}
+
+// newEventBus builds an eventBus. m, if non-nil, has its StreamSubscribers
+// gauge kept in sync with the number of active subscribers.
+func newEventBus(m *metrics.Metrics) *eventBus {
+	return &eventBus{subs: make(map[chan Event]struct{}), m: m}
+}
+
+func (b *eventBus) publish(key string, value interface{}) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.version++
+	ev := Event{Key: key, Value: value, Version: b.version, TS: time.Now().UTC()}
+
+	b.history = append(b.history, ev)
+	if len(b.history) > eventHistoryLimit {
+		b.history = b.history[len(b.history)-eventHistoryLimit:]
+	}
+
+	for sub := range b.subs {
+		select {
+		case sub <- ev:
+		default:
+		}
+	}
+	return ev
+}
+
+// subscribe returns a channel of events with Version > sinceVersion,
+// replaying any matching backlog before the channel starts receiving live
+// publishes. The channel is closed when ctx is done.
+func (b *eventBus) subscribe(ctx context.Context, sinceVersion int64) <-chan Event {
+	b.mu.Lock()
+	var backlog []Event
+	for _, ev := range b.history {
+		if ev.Version > sinceVersion {
+			backlog = append(backlog, ev)
+		}
+	}
+	ch := make(chan Event, len(backlog)+16)
+	for _, ev := range backlog {
+		ch <- ev
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+	if b.m != nil {
+		b.m.StreamSubscribers.Inc()
+	}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, ch)
+		b.mu.Unlock()
+		if b.m != nil {
+			b.m.StreamSubscribers.Dec()
+		}
+		close(ch)
+	}()
+	return ch
+}
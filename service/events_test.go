@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestEventBusSubscribeFromStartReceivesPublishedEvent(t *testing.T) {
+	b := newEventBus(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := b.subscribe(ctx, 0)
+	b.publish("k", "v1")
+
+	select {
+	case ev := <-ch:
+		if ev.Key != "k" || ev.Value != "v1" || ev.Version != 1 {
+			t.Fatalf("got %+v, want Key=k Value=v1 Version=1", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+}
+
+func TestEventBusSubscribeResumesFromVersion(t *testing.T) {
+	b := newEventBus(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.publish("k", "v1") // version 1
+	b.publish("k", "v2") // version 2
+	b.publish("k", "v3") // version 3
+
+	// A subscriber resuming after version 1 must replay 2 and 3, not 1.
+	ch := b.subscribe(ctx, 1)
+
+	var got []Event
+	for i := 0; i < 2; i++ {
+		select {
+		case ev := <-ch:
+			got = append(got, ev)
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for backlog event %d", i)
+		}
+	}
+
+	if len(got) != 2 || got[0].Version != 2 || got[1].Version != 3 {
+		t.Fatalf("got %+v, want versions [2 3]", got)
+	}
+}
+
+func TestEventBusSubscribeFromMostRecentSkipsBacklog(t *testing.T) {
+	b := newEventBus(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b.publish("k", "v1")
+	b.publish("k", "v2")
+
+	ch := b.subscribe(ctx, b.version)
+	b.publish("k", "v3")
+
+	select {
+	case ev := <-ch:
+		if ev.Version != 3 {
+			t.Fatalf("got version %d, want 3 (no backlog replay)", ev.Version)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	select {
+	case ev, open := <-ch:
+		if open {
+			t.Fatalf("got unexpected extra event %+v", ev)
+		}
+	default:
+	}
+}
+
+func TestEventBusSubscribeClosesChannelWhenContextDone(t *testing.T) {
+	b := newEventBus(nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch := b.subscribe(ctx, 0)
+	cancel()
+
+	select {
+	case _, open := <-ch:
+		if open {
+			t.Fatal("channel received a value instead of closing")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for channel to close")
+	}
+}
+
+func TestEventBusHistoryIsBounded(t *testing.T) {
+	b := newEventBus(nil)
+	for i := 0; i < eventHistoryLimit+10; i++ {
+		b.publish("k", i)
+	}
+	if len(b.history) != eventHistoryLimit {
+		t.Fatalf("len(history) = %d, want %d", len(b.history), eventHistoryLimit)
+	}
+	if b.history[0].Version != 11 {
+		t.Fatalf("oldest retained version = %d, want 11 (the 10 oldest dropped)", b.history[0].Version)
+	}
+}
@@ -0,0 +1,210 @@
+// Package service implements the core feature-flag business logic: the
+// in-memory flag cache, its durable Storage backend, and the write-ahead
+// log that makes every mutation crash-safe. Transports (HTTP, gRPC) are
+// kept out of this package entirely so they can be added or swapped without
+// touching the domain logic.
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/auth"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/metrics"
+	"github.com/build-a-thing-and-show/feature-flag-service/requestid"
+	"github.com/build-a-thing-and-show/feature-flag-service/storage"
+	"github.com/build-a-thing-and-show/feature-flag-service/wal"
+)
+
+// Service defines the interface for managing feature flags
+type Service interface {
+	// GetFeatureFlag resolves key to a variation for evalContext: the
+	// variation of the first matching rule, or the flag's default if none
+	// match (or if the flag does not exist, in which case it returns false).
+	GetFeatureFlag(ctx context.Context, key string, evalContext flags.Context) (interface{}, error)
+	SetFeatureFlag(ctx context.Context, key string, flag flags.Flag) error
+	// ListFlags returns every configured flag, keyed by flag key. It backs
+	// /evaluate-all, which resolves every flag for one context in a single
+	// round-trip.
+	ListFlags(ctx context.Context) (map[string]flags.Flag, error)
+	// Subscribe streams an Event for every SetFeatureFlag from this point
+	// on, plus any buffered events with Version > sinceVersion so a caller
+	// resuming after a disconnect doesn't miss mutations. The channel is
+	// closed when ctx is done.
+	Subscribe(ctx context.Context, sinceVersion int64) (<-chan Event, error)
+	// QueryAudit returns every recorded mutation of key, oldest first. It
+	// backs /audit?key=...
+	QueryAudit(ctx context.Context, key string) ([]audit.Record, error)
+}
+
+// featureFlagService is a concrete implementation of Service. It keeps an
+// in-memory cache for fast reads, backed by a durable Storage implementation
+// and a write-ahead log that makes every mutation crash-safe before the
+// cache is updated.
+type featureFlagService struct {
+	store storage.Storage
+	log   *wal.WAL
+	bus   *eventBus
+	audit audit.Sink
+
+	flags map[string]flags.Flag
+	mu    sync.RWMutex
+}
+
+// New builds a Service over store, replaying entries from the WAL at
+// walPath to rebuild in-memory state before it serves requests. Every
+// mutation is recorded to auditSink. m, if non-nil, receives the
+// StreamSubscribers gauge updates for Subscribe.
+func New(store storage.Storage, log *wal.WAL, walPath string, auditSink audit.Sink, m *metrics.Metrics) (Service, error) {
+	s := &featureFlagService{
+		store: store,
+		log:   log,
+		bus:   newEventBus(m),
+		audit: auditSink,
+		flags: make(map[string]flags.Flag),
+	}
+
+	// The WAL is fsync'd before SetFeatureFlag writes to store, so for a
+	// store this node exclusively owns (e.g. BoltStore's local file), a key
+	// the WAL covers is always at least as fresh as what store.List returns
+	// below — a crash between the two leaves the store stale, not the WAL.
+	// That does NOT hold for a shared store like RedisStore: another
+	// replica can have advanced it past this node's own WAL, so there the
+	// store must stay authoritative and the WAL only fills in whatever it
+	// doesn't (yet) have.
+	_, preferWAL := store.(storage.ExclusivelyOwned)
+
+	walKeys := make(map[string]struct{})
+	if err := wal.Replay(walPath, func(e wal.Entry) error {
+		f, err := decodeFlag(e.Value)
+		if err != nil {
+			return err
+		}
+		s.flags[e.Key] = f
+		walKeys[e.Key] = struct{}{}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	records, err := store.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		if _, coveredByWAL := walKeys[r.Key]; coveredByWAL && preferWAL {
+			continue
+		}
+		f, err := decodeFlag(r.Value)
+		if err != nil {
+			return nil, err
+		}
+		s.flags[r.Key] = f
+	}
+	return s, nil
+}
+
+func (s *featureFlagService) GetFeatureFlag(ctx context.Context, key string, evalContext flags.Context) (interface{}, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, exists := s.flags[key]
+	if !exists {
+		return false, nil
+	}
+	return f.Evaluate(key, evalContext), nil
+}
+
+func (s *featureFlagService) SetFeatureFlag(ctx context.Context, key string, flag flags.Flag) error {
+	encoded, err := encodeFlag(flag)
+	if err != nil {
+		return err
+	}
+
+	actor := actorFromContext(ctx)
+	ts := time.Now().UTC()
+
+	if err := s.log.Append(wal.Entry{
+		Key:   key,
+		Value: encoded,
+		TS:    ts,
+		Actor: actor,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.store.Set(ctx, key, encoded); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	old, existed := s.flags[key]
+	s.flags[key] = flag
+	s.mu.Unlock()
+
+	// The WAL, store, and cache are already durably mutated by this point,
+	// so a flaky audit sink must not turn into a false failure for a write
+	// that actually succeeded, nor stop live subscribers (via bus.publish)
+	// from seeing a change that did take effect. An audit.Write failure
+	// only means the audit trail for this otherwise-applied write is
+	// incomplete.
+	var oldValue interface{}
+	if existed {
+		oldValue = old.Default
+	}
+	_ = s.audit.Write(ctx, audit.Record{
+		RequestID: requestid.FromOrNew(ctx),
+		Actor:     actor,
+		Key:       key,
+		OldValue:  oldValue,
+		NewValue:  flag.Default,
+		TS:        ts,
+	})
+
+	s.bus.publish(key, flag.Default)
+	return nil
+}
+
+func (s *featureFlagService) QueryAudit(ctx context.Context, key string) ([]audit.Record, error) {
+	return s.audit.Query(ctx, key)
+}
+
+func (s *featureFlagService) Subscribe(ctx context.Context, sinceVersion int64) (<-chan Event, error) {
+	return s.bus.subscribe(ctx, sinceVersion), nil
+}
+
+func (s *featureFlagService) ListFlags(ctx context.Context) (map[string]flags.Flag, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make(map[string]flags.Flag, len(s.flags))
+	for k, v := range s.flags {
+		out[k] = v
+	}
+	return out, nil
+}
+
+// actorFromContext resolves the identity to record alongside a mutation.
+// Deployments that run without --jwks-url have no authenticated identity,
+// so those writes are attributed to "anonymous".
+func actorFromContext(ctx context.Context) string {
+	if claims, ok := auth.FromContext(ctx); ok && claims.Subject != "" {
+		return claims.Subject
+	}
+	return "anonymous"
+}
+
+func encodeFlag(f flags.Flag) ([]byte, error) {
+	return json.Marshal(f)
+}
+
+func decodeFlag(v []byte) (flags.Flag, error) {
+	var f flags.Flag
+	if len(v) == 0 {
+		return f, nil
+	}
+	err := json.Unmarshal(v, &f)
+	return f, err
+}
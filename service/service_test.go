@@ -0,0 +1,252 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/audit"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+	"github.com/build-a-thing-and-show/feature-flag-service/storage"
+	"github.com/build-a-thing-and-show/feature-flag-service/wal"
+)
+
+// sharedStore is a storage.Storage that does NOT implement
+// storage.ExclusivelyOwned, standing in for RedisStore: its state can be
+// advanced by a writer other than the featureFlagService under test, same
+// as another replica writing to the real shared Redis instance.
+type sharedStore struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newSharedStore() *sharedStore { return &sharedStore{values: make(map[string][]byte)} }
+
+func (s *sharedStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.values[key]
+	return v, ok, nil
+}
+
+func (s *sharedStore) Set(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return nil
+}
+
+func (s *sharedStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+func (s *sharedStore) List(_ context.Context) ([]storage.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records := make([]storage.Record, 0, len(s.values))
+	for k, v := range s.values {
+		records = append(records, storage.Record{Key: k, Value: v})
+	}
+	return records, nil
+}
+
+func (s *sharedStore) Watch(ctx context.Context) (<-chan storage.Record, error) {
+	ch := make(chan storage.Record)
+	go func() {
+		<-ctx.Done()
+		close(ch)
+	}()
+	return ch, nil
+}
+
+// TestNewSurvivesCrashBetweenWALAppendAndStoreSet reproduces the crash
+// SetFeatureFlag's durability guarantee is supposed to survive: the WAL is
+// fsync'd before store.Set runs, so a crash in that window leaves the WAL
+// with the newer value and the store with a stale one. New must treat the
+// WAL as authoritative for any key it covers, not let the stale store value
+// win on replay.
+func TestNewSurvivesCrashBetweenWALAppendAndStoreSet(t *testing.T) {
+	dir := t.TempDir()
+	walPath := dir + "/flags.wal"
+
+	store, err := storage.NewBoltStore(dir + "/flags.db")
+	if err != nil {
+		t.Fatalf("NewBoltStore: %v", err)
+	}
+	w, err := wal.Open(walPath)
+	if err != nil {
+		t.Fatalf("wal.Open: %v", err)
+	}
+
+	svc, err := service.New(store, w, walPath, audit.NewStdoutSink(io.Discard), nil)
+	if err != nil {
+		t.Fatalf("service.New: %v", err)
+	}
+	if err := svc.SetFeatureFlag(context.Background(), "k", flags.Flag{Default: "v1"}); err != nil {
+		t.Fatalf("SetFeatureFlag(v1): %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("wal.Close: %v", err)
+	}
+
+	// Simulate a crash between the WAL append and the store write for v2:
+	// append only the WAL entry, never call store.Set.
+	w2, err := wal.Open(walPath)
+	if err != nil {
+		t.Fatalf("re-wal.Open: %v", err)
+	}
+	if err := w2.Append(wal.Entry{Key: "k", Value: []byte(`{"default":"v2"}`)}); err != nil {
+		t.Fatalf("Append(v2): %v", err)
+	}
+	if err := w2.Close(); err != nil {
+		t.Fatalf("wal.Close: %v", err)
+	}
+
+	// Restart: store still only has v1, but the WAL has v1 then v2.
+	w3, err := wal.Open(walPath)
+	if err != nil {
+		t.Fatalf("re-wal.Open: %v", err)
+	}
+	restarted, err := service.New(store, w3, walPath, audit.NewStdoutSink(io.Discard), nil)
+	if err != nil {
+		t.Fatalf("service.New after crash: %v", err)
+	}
+
+	got, err := restarted.GetFeatureFlag(context.Background(), "k", flags.Context{})
+	if err != nil {
+		t.Fatalf("GetFeatureFlag: %v", err)
+	}
+	if got != "v2" {
+		t.Fatalf("GetFeatureFlag() = %v, want %q (WAL must win over the stale store value)", got, "v2")
+	}
+}
+
+// TestNewPrefersSharedStoreOverOwnStaleWALOnSharedBackend reproduces the
+// multi-replica scenario RedisStore exists for: replica A sets k=true
+// (its own local WAL + the shared store), replica B later advances the
+// shared store to k=false through a separate local WAL, and when A
+// restarts it must see the shared store's current value, not keep
+// preferring its own now-stale WAL forever.
+func TestNewPrefersSharedStoreOverOwnStaleWALOnSharedBackend(t *testing.T) {
+	dir := t.TempDir()
+	shared := newSharedStore()
+
+	walPathA := dir + "/a.wal"
+	wA, err := wal.Open(walPathA)
+	if err != nil {
+		t.Fatalf("wal.Open(A): %v", err)
+	}
+	svcA, err := service.New(shared, wA, walPathA, audit.NewStdoutSink(io.Discard), nil)
+	if err != nil {
+		t.Fatalf("service.New(A): %v", err)
+	}
+	if err := svcA.SetFeatureFlag(context.Background(), "k", flags.Flag{Default: true}); err != nil {
+		t.Fatalf("SetFeatureFlag(A, true): %v", err)
+	}
+	if err := wA.Close(); err != nil {
+		t.Fatalf("wal.Close(A): %v", err)
+	}
+
+	// Replica B, a separate process with its own local WAL, writes a newer
+	// value to the same shared store.
+	walPathB := dir + "/b.wal"
+	wB, err := wal.Open(walPathB)
+	if err != nil {
+		t.Fatalf("wal.Open(B): %v", err)
+	}
+	svcB, err := service.New(shared, wB, walPathB, audit.NewStdoutSink(io.Discard), nil)
+	if err != nil {
+		t.Fatalf("service.New(B): %v", err)
+	}
+	if err := svcB.SetFeatureFlag(context.Background(), "k", flags.Flag{Default: false}); err != nil {
+		t.Fatalf("SetFeatureFlag(B, false): %v", err)
+	}
+
+	// Replica A restarts: its own WAL still only knows about true, but the
+	// shared store now holds false.
+	wA2, err := wal.Open(walPathA)
+	if err != nil {
+		t.Fatalf("re-wal.Open(A): %v", err)
+	}
+	restartedA, err := service.New(shared, wA2, walPathA, audit.NewStdoutSink(io.Discard), nil)
+	if err != nil {
+		t.Fatalf("service.New(A) after restart: %v", err)
+	}
+
+	got, err := restartedA.GetFeatureFlag(context.Background(), "k", flags.Context{})
+	if err != nil {
+		t.Fatalf("GetFeatureFlag: %v", err)
+	}
+	if got != false {
+		t.Fatalf("GetFeatureFlag() = %v, want false (the shared store's current value, not A's stale WAL)", got)
+	}
+}
+
+// erroringAuditSink always fails Write, simulating a down audit backend
+// (e.g. Kafka unreachable).
+type erroringAuditSink struct{}
+
+func (erroringAuditSink) Write(context.Context, audit.Record) error {
+	return errors.New("audit sink unavailable")
+}
+
+func (erroringAuditSink) Query(context.Context, string) ([]audit.Record, error) {
+	return nil, nil
+}
+
+// TestSetFeatureFlagSucceedsAndPublishesDespiteAuditFailure pins that a
+// flaky audit sink can't turn an otherwise-successful write (WAL + store +
+// cache all applied) into a reported failure, and can't starve live
+// Subscribe-based subscribers of the mutation that did take effect.
+func TestSetFeatureFlagSucceedsAndPublishesDespiteAuditFailure(t *testing.T) {
+	dir := t.TempDir()
+	walPath := dir + "/flags.wal"
+
+	store, err := storage.NewBoltStore(dir + "/flags.db")
+	if err != nil {
+		t.Fatalf("NewBoltStore: %v", err)
+	}
+	w, err := wal.Open(walPath)
+	if err != nil {
+		t.Fatalf("wal.Open: %v", err)
+	}
+	svc, err := service.New(store, w, walPath, erroringAuditSink{}, nil)
+	if err != nil {
+		t.Fatalf("service.New: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := svc.Subscribe(ctx, 0)
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	if err := svc.SetFeatureFlag(context.Background(), "k", flags.Flag{Default: "v1"}); err != nil {
+		t.Fatalf("SetFeatureFlag returned an error despite the mutation succeeding: %v", err)
+	}
+
+	got, err := svc.GetFeatureFlag(context.Background(), "k", flags.Context{})
+	if err != nil {
+		t.Fatalf("GetFeatureFlag: %v", err)
+	}
+	if got != "v1" {
+		t.Fatalf("GetFeatureFlag() = %v, want %q", got, "v1")
+	}
+
+	select {
+	case ev := <-events:
+		if ev.Key != "k" || ev.Value != "v1" {
+			t.Fatalf("got %+v, want Key=k Value=v1", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the published event despite the audit sink failing")
+	}
+}
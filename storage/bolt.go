@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketName = []byte("flags")
+
+// BoltStore is a Storage backed by a local BoltDB file. It is the default
+// backend for single-instance deployments.
+type BoltStore struct {
+	db *bolt.DB
+
+	mu   sync.Mutex
+	subs []chan Record
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path and
+// ensures the flags bucket exists.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketName).Get([]byte(key))
+		if v != nil {
+			value = append([]byte(nil), v...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return value, value != nil, nil
+}
+
+func (s *BoltStore) Set(ctx context.Context, key string, value []byte) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), value)
+	})
+	if err != nil {
+		return err
+	}
+	s.notify(Record{Key: key, Value: value})
+	return nil
+}
+
+func (s *BoltStore) Delete(ctx context.Context, key string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+	if err != nil {
+		return err
+	}
+	s.notify(Record{Key: key, Value: nil})
+	return nil
+}
+
+func (s *BoltStore) List(ctx context.Context) ([]Record, error) {
+	var records []Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).ForEach(func(k, v []byte) error {
+			records = append(records, Record{Key: string(k), Value: append([]byte(nil), v...)})
+			return nil
+		})
+	})
+	return records, err
+}
+
+func (s *BoltStore) Watch(ctx context.Context) (<-chan Record, error) {
+	ch := make(chan Record, 16)
+	s.mu.Lock()
+	s.subs = append(s.subs, ch)
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, sub := range s.subs {
+			if sub == ch {
+				s.subs = append(s.subs[:i], s.subs[i+1:]...)
+				break
+			}
+		}
+		close(ch)
+	}()
+	return ch, nil
+}
+
+func (s *BoltStore) notify(r Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subs {
+		select {
+		case sub <- r:
+		default:
+		}
+	}
+}
+
+// Close releases the underlying BoltDB file handle.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// exclusivelyOwned marks BoltStore as ExclusivelyOwned: its file belongs to
+// this process alone, so nothing else can advance it past this node's WAL.
+func (s *BoltStore) exclusivelyOwned() {}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// redisKeyspace namespaces every flag key so the store can share a Redis
+// instance with other data.
+const redisKeyspace = "featureflags:"
+
+// RedisStore is a Storage backed by Redis, used when running multiple
+// feature-flag replicas against shared state.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore connects to the Redis instance at addr ("host:port").
+func NewRedisStore(addr string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.client.Get(ctx, redisKeyspace+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte) error {
+	if err := s.client.Set(ctx, redisKeyspace+key, value, 0).Err(); err != nil {
+		return err
+	}
+	return s.publish(ctx, Record{Key: key, Value: value})
+}
+
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, redisKeyspace+key).Err(); err != nil {
+		return err
+	}
+	return s.publish(ctx, Record{Key: key, Value: nil})
+}
+
+// publish broadcasts r to every Watch subscriber. The payload is the Record
+// itself (JSON-encoded) so a subscriber can recover the key a Set or Delete
+// applies to, not just the fact that something changed.
+func (s *RedisStore) publish(ctx context.Context, r Record) error {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, redisKeyspace+"events", payload).Err()
+}
+
+func (s *RedisStore) List(ctx context.Context) ([]Record, error) {
+	keys, err := s.client.Keys(ctx, redisKeyspace+"*").Result()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Record, 0, len(keys))
+	for _, k := range keys {
+		if k == redisKeyspace+"events" {
+			continue
+		}
+		v, err := s.client.Get(ctx, k).Bytes()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, Record{Key: k[len(redisKeyspace):], Value: v})
+	}
+	return records, nil
+}
+
+func (s *RedisStore) Watch(ctx context.Context) (<-chan Record, error) {
+	sub := s.client.Subscribe(ctx, redisKeyspace+"events")
+	ch := make(chan Record, 16)
+	go func() {
+		<-ctx.Done()
+		sub.Close()
+	}()
+	go func() {
+		defer close(ch)
+		defer sub.Close()
+		for msg := range sub.Channel() {
+			var r Record
+			if err := json.Unmarshal([]byte(msg.Payload), &r); err != nil {
+				continue
+			}
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// Close releases the underlying Redis client.
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,71 @@
+// Package storage defines the persistence backend used by featureFlagService
+// to hold flag state, along with the implementations the service can be
+// configured to use.
+package storage
+
+import "context"
+
+// Record is a single stored key/value pair, as returned by List and Watch.
+type Record struct {
+	Key   string
+	Value []byte
+}
+
+// Storage is the persistence interface backing feature flag state. The
+// service treats values as opaque bytes (it owns marshaling/unmarshaling of
+// the flag representation) so that a Storage implementation never needs to
+// know about the Flag type.
+//
+// Implementations must be safe for concurrent use.
+type Storage interface {
+	// Get returns the raw value for key, and false if it does not exist.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set durably writes value for key, creating or overwriting it.
+	Set(ctx context.Context, key string, value []byte) error
+
+	// Delete removes key. It is not an error to delete a missing key.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every key/value pair currently stored.
+	List(ctx context.Context) ([]Record, error)
+
+	// Watch streams every subsequent Set/Delete as a Record (a Delete is
+	// sent with a nil Value). The channel is closed when ctx is done;
+	// closing the store does not close it.
+	Watch(ctx context.Context) (<-chan Record, error)
+}
+
+// ExclusivelyOwned is implemented by Storage backends whose persisted state
+// belongs to exactly one process, such as BoltStore's local file — as
+// opposed to a shared backend like RedisStore that multiple replicas write
+// to concurrently. service.New uses this to decide whether its own
+// write-ahead log may be treated as more up to date than the store on
+// replay: that's only true when nothing else could have advanced the store
+// past what this node's WAL recorded.
+type ExclusivelyOwned interface {
+	Storage
+	exclusivelyOwned()
+}
+
+// New builds a Storage backend for the given name ("bolt" or "redis").
+// addr is interpreted as a filesystem path for "bolt" and as a Redis
+// connection address ("host:port") for "redis".
+func New(name, addr string) (Storage, error) {
+	switch name {
+	case "bolt", "":
+		return NewBoltStore(addr)
+	case "redis":
+		return NewRedisStore(addr)
+	default:
+		return nil, ErrUnknownBackend(name)
+	}
+}
+
+// ErrUnknownBackend is returned by New when name does not match a known
+// storage backend.
+type ErrUnknownBackend string
+
+func (e ErrUnknownBackend) Error() string {
+	return "storage: unknown backend " + string(e)
+}
@@ -0,0 +1,43 @@
+// Package tracing wires OpenTelemetry spans around requests, propagating
+// W3C traceparent headers across the HTTP transport so this service's spans
+// join a caller's trace instead of starting a new one.
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	kitendpoint "github.com/go-kit/kit/endpoint"
+)
+
+// tracerName identifies this service's spans in a multi-service trace.
+const tracerName = "github.com/build-a-thing-and-show/feature-flag-service"
+
+// HTTPToContext is a go-kit transport/http.RequestFunc that extracts a
+// traceparent header, if present, into the request context, so spans
+// started later in the call chain join the caller's trace.
+func HTTPToContext() func(ctx context.Context, r *http.Request) context.Context {
+	return func(ctx context.Context, r *http.Request) context.Context {
+		return propagation.TraceContext{}.Extract(ctx, propagation.HeaderCarrier(r.Header))
+	}
+}
+
+// Middleware returns an endpoint.Middleware that starts a span named name
+// around every call and records the call's error on it, if any.
+func Middleware(name string) kitendpoint.Middleware {
+	tracer := otel.Tracer(tracerName)
+	return func(next kitendpoint.Endpoint) kitendpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			ctx, span := tracer.Start(ctx, name)
+			defer span.End()
+			resp, err := next(ctx, request)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package grpc provides a gRPC transport over the feature-flag service's
+// endpoints, for latency-sensitive callers that want to skip JSON/HTTP
+// overhead. It decodes/encodes onto the same endpoint.Endpoints the HTTP
+// transport uses, via go-kit's grpc transport helpers.
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/auth"
+	"github.com/build-a-thing-and-show/feature-flag-service/endpoint"
+	"github.com/build-a-thing-and-show/feature-flag-service/flags"
+	"github.com/build-a-thing-and-show/feature-flag-service/pb"
+	"github.com/build-a-thing-and-show/feature-flag-service/requestid"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+)
+
+type grpcServer struct {
+	get           kitgrpc.Handler
+	set           kitgrpc.Handler
+	list          kitgrpc.Handler
+	svc           service.Service
+	authenticator *auth.Authenticator
+
+	pb.UnimplementedFeatureFlagServiceServer
+}
+
+// NewServer builds a pb.FeatureFlagServiceServer that serves endpoints over
+// gRPC. svc backs WatchFlags directly, since a server-streaming RPC doesn't
+// fit go-kit's request/response endpoint shape; if authenticator is
+// non-nil, WatchFlags enforces the same RequireRead RBAC as Get/Evaluate
+// instead (the request/response endpoints get it via endpoints' own
+// middleware, built by endpoint.MakeServerEndpoints).
+func NewServer(svc service.Service, endpoints endpoint.Endpoints, authenticator *auth.Authenticator) pb.FeatureFlagServiceServer {
+	requestIDBefore := kitgrpc.ServerBefore(requestid.GRPCToContext)
+	return &grpcServer{
+		get: kitgrpc.NewServer(
+			endpoints.GetFeatureFlagEndpoint,
+			decodeGetFeatureFlagRequest,
+			encodeGetFeatureFlagResponse,
+			requestIDBefore,
+		),
+		set: kitgrpc.NewServer(
+			endpoints.SetFeatureFlagEndpoint,
+			decodeSetFeatureFlagRequest,
+			encodeSetFeatureFlagResponse,
+			requestIDBefore,
+		),
+		list: kitgrpc.NewServer(
+			endpoints.ListFlagsEndpoint,
+			decodeListFlagsRequest,
+			encodeListFlagsResponse,
+			requestIDBefore,
+		),
+		svc:           svc,
+		authenticator: authenticator,
+	}
+}
+
+func (s *grpcServer) GetFeatureFlag(ctx context.Context, req *pb.GetFeatureFlagRequest) (*pb.GetFeatureFlagResponse, error) {
+	_, resp, err := s.get.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.GetFeatureFlagResponse), nil
+}
+
+func (s *grpcServer) SetFeatureFlag(ctx context.Context, req *pb.SetFeatureFlagRequest) (*pb.SetFeatureFlagResponse, error) {
+	_, resp, err := s.set.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.SetFeatureFlagResponse), nil
+}
+
+func (s *grpcServer) ListFlags(ctx context.Context, req *pb.ListFlagsRequest) (*pb.ListFlagsResponse, error) {
+	_, resp, err := s.list.ServeGRPC(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ListFlagsResponse), nil
+}
+
+// WatchFlags streams every subsequent SetFeatureFlag as a FlagEvent,
+// filtered to req.Keys if non-empty. It has no resume-from-version cursor
+// (see /stream's Last-Event-ID on the HTTP transport for that); every call
+// starts from the current version.
+func (s *grpcServer) WatchFlags(req *pb.WatchFlagsRequest, stream pb.FeatureFlagService_WatchFlagsServer) error {
+	if s.authenticator != nil {
+		claims, ok := auth.FromContext(stream.Context())
+		if !ok {
+			return status.Error(codes.Unauthenticated, auth.ErrUnauthenticated.Error())
+		}
+		if !claims.CanRead() {
+			return status.Error(codes.PermissionDenied, auth.ErrForbidden.Error())
+		}
+	}
+
+	watched := make(map[string]bool, len(req.Keys))
+	for _, k := range req.Keys {
+		watched[k] = true
+	}
+
+	events, err := s.svc.Subscribe(stream.Context(), mostRecentVersion)
+	if err != nil {
+		return err
+	}
+
+	for ev := range events {
+		if len(watched) > 0 && !watched[ev.Key] {
+			continue
+		}
+		valueJSON, err := json.Marshal(ev.Value)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(&pb.FlagEvent{
+			Key:       ev.Key,
+			ValueJson: string(valueJSON),
+			Version:   ev.Version,
+			UnixTs:    ev.TS.Unix(),
+		}); err != nil {
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+// mostRecentVersion tells Subscribe to skip replaying buffered history and
+// only stream mutations from this point on, since the WatchFlags RPC has no
+// concept of a resume cursor.
+const mostRecentVersion = 1<<63 - 1
+
+func decodeGetFeatureFlagRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.GetFeatureFlagRequest)
+	var evalContext flags.Context
+	if req.ContextJson != "" {
+		if err := json.Unmarshal([]byte(req.ContextJson), &evalContext); err != nil {
+			return nil, err
+		}
+	}
+	return endpoint.GetFeatureFlagRequest{Key: req.Key, Context: evalContext}, nil
+}
+
+func encodeGetFeatureFlagResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoint.GetFeatureFlagResponse)
+	valueJSON, err := json.Marshal(resp.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetFeatureFlagResponse{ValueJson: string(valueJSON)}, nil
+}
+
+func decodeSetFeatureFlagRequest(_ context.Context, grpcReq interface{}) (interface{}, error) {
+	req := grpcReq.(*pb.SetFeatureFlagRequest)
+	var f flags.Flag
+	if err := json.Unmarshal([]byte(req.FlagJson), &f); err != nil {
+		return nil, err
+	}
+	return endpoint.SetFeatureFlagRequest{Key: req.Key, Flag: f}, nil
+}
+
+func encodeSetFeatureFlagResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoint.SetFeatureFlagResponse)
+	return &pb.SetFeatureFlagResponse{Success: resp.Success}, nil
+}
+
+func decodeListFlagsRequest(_ context.Context, _ interface{}) (interface{}, error) {
+	return endpoint.ListFlagsRequest{}, nil
+}
+
+func encodeListFlagsResponse(_ context.Context, response interface{}) (interface{}, error) {
+	resp := response.(endpoint.ListFlagsResponse)
+	out := make(map[string]string, len(resp.Flags))
+	for key, f := range resp.Flags {
+		b, err := json.Marshal(f)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = string(b)
+	}
+	return &pb.ListFlagsResponse{FlagsJson: out}, nil
+}
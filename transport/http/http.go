@@ -0,0 +1,145 @@
+// Package http provides an HTTP/JSON transport over the feature-flag
+// service's endpoints.
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	kitHttp "github.com/go-kit/kit/transport/http"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/build-a-thing-and-show/feature-flag-service/auth"
+	"github.com/build-a-thing-and-show/feature-flag-service/endpoint"
+	"github.com/build-a-thing-and-show/feature-flag-service/requestid"
+	"github.com/build-a-thing-and-show/feature-flag-service/service"
+	"github.com/build-a-thing-and-show/feature-flag-service/tracing"
+)
+
+// NewHandler builds an http.Handler that dispatches to /get, /set,
+// /evaluate, /evaluate-all, /audit, /stream, and /ws. If authenticator is
+// non-nil, every request's bearer token (if any) is resolved to Claims
+// before reaching the endpoints, which enforce RBAC themselves; /stream and
+// /ws enforce the same RequireRead RBAC directly, since their long-lived
+// handlers bypass the endpoint middleware chain. If authenticator is nil,
+// auth is disabled and every route is open. Every request's traceparent and
+// X-Request-Id headers, if present, are extracted into context regardless
+// of auth, the latter so the audit record SetFeatureFlag writes can be
+// correlated back to the request that produced it.
+func NewHandler(svc service.Service, endpoints endpoint.Endpoints, authenticator *auth.Authenticator) http.Handler {
+	mux := http.NewServeMux()
+
+	options := []kitHttp.ServerOption{
+		kitHttp.ServerBefore(tracing.HTTPToContext()),
+		kitHttp.ServerBefore(requestid.HTTPToContext()),
+	}
+	if authenticator != nil {
+		options = append(options,
+			kitHttp.ServerBefore(authenticator.HTTPToContext()),
+			kitHttp.ServerErrorEncoder(encodeError),
+		)
+	}
+
+	mux.Handle("/get", kitHttp.NewServer(
+		endpoints.GetFeatureFlagEndpoint,
+		decodeGetFeatureFlagRequest,
+		encodeResponse,
+		options...,
+	))
+
+	mux.Handle("/set", kitHttp.NewServer(
+		endpoints.SetFeatureFlagEndpoint,
+		decodeSetFeatureFlagRequest,
+		encodeResponse,
+		options...,
+	))
+
+	mux.Handle("/evaluate", kitHttp.NewServer(
+		endpoints.EvaluateEndpoint,
+		decodeGetFeatureFlagRequest,
+		encodeResponse,
+		options...,
+	))
+
+	mux.Handle("/evaluate-all", kitHttp.NewServer(
+		endpoints.EvaluateAllEndpoint,
+		decodeEvaluateAllRequest,
+		encodeResponse,
+		options...,
+	))
+
+	mux.Handle("/audit", kitHttp.NewServer(
+		endpoints.AuditEndpoint,
+		decodeAuditRequest,
+		encodeResponse,
+		options...,
+	))
+
+	mux.HandleFunc("/stream", handleStream(svc, authenticator))
+	mux.HandleFunc("/ws", handleWS(svc, authenticator))
+	mux.HandleFunc("/health", handleHealth)
+
+	return mux
+}
+
+// NewAdminHandler builds the http.Handler for the admin port: /metrics, kept
+// separate from NewHandler's flag traffic so scraping never competes with
+// it for a listener.
+func NewAdminHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// handleHealth backs the Consul HTTP health check registered by the
+// registry package; a 200 means this instance is eligible for discovery.
+func handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func decodeGetFeatureFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request endpoint.GetFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeSetFeatureFlagRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request endpoint.SetFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeEvaluateAllRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var request endpoint.EvaluateAllRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func decodeAuditRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return endpoint.AuditRequest{Key: r.URL.Query().Get("key")}, nil
+}
+
+func encodeResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	return json.NewEncoder(w).Encode(response)
+}
+
+// encodeError maps auth's middleware errors to their HTTP status codes; any
+// other error falls back to go-kit's default of 500.
+func encodeError(_ context.Context, err error, w http.ResponseWriter) {
+	status := http.StatusInternalServerError
+	switch err {
+	case auth.ErrUnauthenticated:
+		status = http.StatusUnauthorized
+	case auth.ErrForbidden:
+		status = http.StatusForbidden
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
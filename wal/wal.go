@@ -0,0 +1,85 @@
+// Package wal implements a simple append-only write-ahead log used to make
+// flag mutations durable before they are applied to the in-memory cache, and
+// to rebuild that cache on startup.
+package wal
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry is a single recorded mutation.
+type Entry struct {
+	Key   string    `json:"key"`
+	Value []byte    `json:"value"`
+	TS    time.Time `json:"ts"`
+	Actor string    `json:"actor"`
+}
+
+// WAL appends Entry records to a file, one JSON object per line, and fsyncs
+// after every write so a crash never loses an acknowledged mutation.
+type WAL struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// Open opens (creating if necessary) the WAL file at path for appending.
+func Open(path string) (*WAL, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &WAL{f: f}, nil
+}
+
+// Append durably records e before returning.
+func (w *WAL) Append(e Entry) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	if _, err := w.f.Write(line); err != nil {
+		return err
+	}
+	return w.f.Sync()
+}
+
+// Replay reads every entry in the log, in order, calling fn for each. It is
+// used on startup to rebuild in-memory state before the service starts
+// serving requests.
+func Replay(path string, fn func(Entry) error) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return err
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Close closes the underlying file.
+func (w *WAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.f.Close()
+}